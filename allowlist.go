@@ -0,0 +1,194 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateTableAllowlist checks every table a config reads, in FROM, as a
+// comma table or joined, is in config.TableAllowlist. A table qualified with
+// a database or schema, by its own Database, by its name or by
+// config.Schema, must be listed qualified, e.g. "analytics.orders"; a bare
+// entry allows only the unqualified table. Names match case-insensitively.
+//
+// Raw filters, subqueries and value expressions can name any table, so they
+// are rejected while an allowlist is set. Column expressions are not parsed
+// here; ValidateColumnAccess restricts them.
+func validateTableAllowlist(config *ChartConfig) error {
+	check := func(where, database, name string) error {
+		if database == "" {
+			database = config.Schema
+		}
+		qualified := name
+		if database != "" {
+			qualified = database + "." + name
+		}
+		for _, allowed := range config.TableAllowlist {
+			if strings.EqualFold(allowed, qualified) {
+				return nil
+			}
+		}
+		return fmt.Errorf("table '%s' at %s is not in the table allowlist", qualified, where)
+	}
+
+	for i, table := range config.Tables {
+		if err := check(fmt.Sprintf("table index %d", i), table.Database, table.Name); err != nil {
+			return err
+		}
+		for j, join := range table.Joins {
+			if err := check(fmt.Sprintf("table index %d, join index %d", i, j), join.Database, join.Table); err != nil {
+				return err
+			}
+		}
+	}
+	for i, join := range config.Joins {
+		if err := check(fmt.Sprintf("joins index %d", i), join.Database, join.Table); err != nil {
+			return err
+		}
+	}
+
+	for _, list := range filterLists(config, "filter") {
+		for i, filter := range list.filters {
+			if filter.Raw != "" || filter.Subquery != "" || filter.ValueExpr != "" {
+				return fmt.Errorf("%s at index %d uses raw SQL, which cannot be checked against the table allowlist", list.name, i)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateColumnAccess checks every column config references against an
+// allowlist of columns per table, e.g. to keep PII columns out of charts end
+// users define:
+//
+//	ValidateColumnAccess(config, map[string][]string{
+//		"orders": {"id", "region", "amount", "user_id"},
+//		"users":  {"id", "country"},
+//	})
+//
+// Keys name a table, optionally schema-qualified as in the config; they are
+// never matched against aliases, which the config author picks. A qualified
+// column o.amount is resolved through its alias to the table and checked
+// against that table's list. A bare
+// column must be allowed for one of the config's tables. x_value and the Y
+// aliases are accepted where they stand for a column. Names match
+// case-insensitively.
+//
+// Every axis, join condition, filter, group-by, having, distinct-on and
+// order-by expression is checked; words in them that are not columns, such
+// as SELECT in a subquery, are rejected too. Raw filters, subqueries and
+// value expressions cannot be checked and are rejected.
+func ValidateColumnAccess(config *ChartConfig, allowed map[string][]string) error {
+	// Each alias or table name maps to the allowlist of its table
+	tables := make(map[string][]string)
+	var order []string
+	add := func(database, name, alias string) {
+		columns, ok := allowedColumns(allowed, database, name)
+		key := alias
+		if key == "" {
+			key = name[strings.LastIndex(name, ".")+1:]
+		}
+		key = strings.ToLower(key)
+		if contains(order, key) {
+			// Two tables under one name are ambiguous; allow neither's columns
+			delete(tables, key)
+			return
+		}
+		order = append(order, key)
+		if ok {
+			tables[key] = columns
+		}
+	}
+	for _, table := range config.Tables {
+		add(table.Database, table.Name, table.Alias)
+		for _, join := range table.Joins {
+			add(join.Database, join.Table, join.Alias)
+		}
+	}
+	for _, join := range config.Joins {
+		add(join.Database, join.Table, join.Alias)
+	}
+
+	aliases := map[string]bool{"x_value": true}
+	for i, yAxis := range config.YAxis {
+		aliases[strings.ToLower(yAlias(i, yAxis))] = true
+	}
+
+	for _, list := range filterLists(config, "filters") {
+		for i, filter := range list.filters {
+			if filter.Raw != "" || filter.Subquery != "" || filter.ValueExpr != "" {
+				return fmt.Errorf("%s[%d]: raw SQL cannot be checked against the column allowlist", list.name, i)
+			}
+		}
+	}
+
+	return visitColumnRefs(config, func(where string, ref ColumnRef, allowAliases bool) error {
+		column := strings.ToLower(ref.Column)
+		if ref.Table == "" {
+			if allowAliases && aliases[column] {
+				return nil
+			}
+			for _, key := range order {
+				if contains(tables[key], column) {
+					return nil
+				}
+			}
+			return fmt.Errorf("%s: column '%s' is not allowed", where, ref.Column)
+		}
+
+		key := strings.ToLower(ref.Table)
+		columns, ok := tables[key]
+		if !ok {
+			if contains(order, key) {
+				return fmt.Errorf("%s: table '%s' has no column allowlist, so column '%s' is not allowed", where, ref.Table, ref)
+			}
+			return fmt.Errorf("%s: column '%s' references unknown table or alias '%s'", where, ref, ref.Table)
+		}
+		if !contains(columns, column) {
+			return fmt.Errorf("%s: column '%s' is not allowed", where, ref)
+		}
+		return nil
+	})
+}
+
+// allowedColumns returns the lower-cased allowlist of a table, looked up by
+// its name qualified with database, else its name as written, else its bare
+// name. The alias is not a candidate: {name: payroll, alias: orders} must not
+// get the orders allowlist.
+func allowedColumns(allowed map[string][]string, database, name string) ([]string, bool) {
+	var candidates []string
+	if database != "" {
+		candidates = append(candidates, database+"."+name)
+	}
+	candidates = append(candidates, name, name[strings.LastIndex(name, ".")+1:])
+	for _, candidate := range candidates {
+		for key, columns := range allowed {
+			if strings.EqualFold(key, candidate) {
+				lower := make([]string, len(columns))
+				for i, column := range columns {
+					lower[i] = strings.ToLower(column)
+				}
+				return lower, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// namedFilters is a list of filters and the config field holding them
+type namedFilters struct {
+	name    string
+	filters []FilterConfig
+}
+
+// filterLists returns every filter list of config: the filters, named
+// filtersName, the having filters and each Y axis's series_filter
+func filterLists(config *ChartConfig, filtersName string) []namedFilters {
+	lists := []namedFilters{{filtersName, config.Filters}, {"having", config.Having}}
+	for i, yAxis := range config.YAxis {
+		if len(yAxis.SeriesFilter) > 0 {
+			lists = append(lists, namedFilters{fmt.Sprintf("y_axis[%d].series_filter", i), yAxis.SeriesFilter})
+		}
+	}
+	return lists
+}
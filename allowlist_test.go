@@ -0,0 +1,105 @@
+package chatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateColumnAccess(t *testing.T) {
+	allowed := map[string][]string{
+		"orders":           {"id", "region", "amount"},
+		"analytics.events": {"id", "kind"},
+	}
+
+	tests := []struct {
+		name   string
+		config func() *ChartConfig
+		want   string // empty when the config must pass
+	}{
+		{
+			name:   "allowed columns through an alias",
+			config: func() *ChartConfig { return testConfig() },
+		},
+		{
+			name: "disallowed column",
+			config: func() *ChartConfig {
+				return testConfig(FilterConfig{Column: "o.email", Operator: "=", Value: "x"})
+			},
+			want: "column 'o.email' is not allowed",
+		},
+		{
+			name: "aliased as an allowed table",
+			config: func() *ChartConfig {
+				config := testConfig()
+				config.Tables = []TableConfig{{Name: "payroll", Alias: "orders"}}
+				config.XAxis.Column = "orders.region"
+				config.YAxis[0].Column = "orders.amount"
+				config.GroupBy = []string{"orders.region"}
+				return config
+			},
+			want: "table 'orders' has no column allowlist",
+		},
+		{
+			name: "aliased as an allowed table, bare columns",
+			config: func() *ChartConfig {
+				config := testConfig()
+				config.Tables = []TableConfig{{Name: "payroll", Alias: "orders"}}
+				config.XAxis.Column = "region"
+				config.YAxis[0].Column = "amount"
+				config.GroupBy = []string{"region"}
+				return config
+			},
+			want: "column 'region' is not allowed",
+		},
+		{
+			name: "alias shadowing an allowed table",
+			config: func() *ChartConfig {
+				config := testConfig()
+				config.Tables = []TableConfig{{Name: "orders"}, {Name: "payroll", Alias: "orders"}}
+				config.XAxis.Column = "orders.region"
+				config.YAxis[0].Column = "orders.amount"
+				config.GroupBy = []string{"orders.region"}
+				return config
+			},
+			want: "no column allowlist",
+		},
+		{
+			name: "schema-qualified table",
+			config: func() *ChartConfig {
+				config := testConfig()
+				config.Tables = []TableConfig{{Name: "events", Database: "analytics", Alias: "e"}}
+				config.XAxis.Column = "e.kind"
+				config.YAxis[0] = AxisConfig{Column: "e.id", Aggregation: "COUNT"}
+				config.GroupBy = []string{"e.kind"}
+				return config
+			},
+		},
+		{
+			name: "table in another schema",
+			config: func() *ChartConfig {
+				config := testConfig()
+				config.Tables = []TableConfig{{Name: "events", Database: "staging", Alias: "e"}}
+				config.XAxis.Column = "e.kind"
+				config.YAxis[0] = AxisConfig{Column: "e.id", Aggregation: "COUNT"}
+				config.GroupBy = []string{"e.kind"}
+				return config
+			},
+			want: "table 'e' has no column allowlist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateColumnAccess(tt.config(), allowed)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("ValidateColumnAccess() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ValidateColumnAccess() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
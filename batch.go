@@ -0,0 +1,63 @@
+package chatabase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultBatchConcurrency is the number of chart queries ExecuteCharts runs at once
+const DefaultBatchConcurrency = 4
+
+// BatchOptions controls ExecuteChartsWithOptions
+type BatchOptions struct {
+	// Concurrency bounds the queries in flight; <= 0 uses DefaultBatchConcurrency.
+	// Keep it below the connection pool size to leave room for other work.
+	Concurrency int
+
+	// Execute is passed to every chart; may be nil
+	Execute *ExecuteOptions
+}
+
+// ExecuteCharts runs several chart queries concurrently, e.g. for a dashboard.
+// Results and errors are aligned with configs by index; one failing chart,
+// including a nil config, does not stop the others.
+func ExecuteCharts(ctx context.Context, db *sqlx.DB, configs []*ChartConfig) ([][]ChartDataRow, []error) {
+	return ExecuteChartsWithOptions(ctx, db, configs, BatchOptions{})
+}
+
+// ExecuteChartsWithOptions is ExecuteCharts with a configurable concurrency.
+// Each query runs with ctx, so cancelling it stops queries in flight, and
+// charts not yet started fail with the context's error.
+func ExecuteChartsWithOptions(ctx context.Context, db *sqlx.DB, configs []*ChartConfig, opts BatchOptions) ([][]ChartDataRow, []error) {
+	results := make([][]ChartDataRow, len(configs))
+	errs := make([]error, len(configs))
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+	for i, config := range configs {
+		// A nil config would panic inside the goroutine, beyond the caller's recover
+		if config == nil {
+			errs[i] = fmt.Errorf("chart config at index %d is nil", i)
+			continue
+		}
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return nil
+			}
+			results[i], errs[i] = ExecuteChartContext(ctx, db, config, opts.Execute)
+			return nil
+		})
+	}
+	g.Wait()
+
+	return results, errs
+}
@@ -0,0 +1,24 @@
+package chatabase
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExecuteChartsNilConfig(t *testing.T) {
+	invalid := testConfig()
+	invalid.Title = ""
+
+	// Neither config reaches the database, so none is needed
+	results, errs := ExecuteCharts(context.Background(), nil, []*ChartConfig{nil, invalid})
+	if len(results) != 2 || len(errs) != 2 {
+		t.Fatalf("got %d results and %d errors, want 2 of each", len(results), len(errs))
+	}
+	if errs[0] == nil || !strings.Contains(errs[0].Error(), "index 0 is nil") {
+		t.Errorf("errs[0] = %v, want a nil config error", errs[0])
+	}
+	if errs[1] == nil {
+		t.Error("errs[1] = nil, want a validation error")
+	}
+}
@@ -0,0 +1,326 @@
+package chatabase
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// Cache stores serialized chart results. Implementations must be safe for
+// concurrent use; a Redis-backed cache only needs to map these onto
+// GET, SET with EX and DEL.
+type Cache interface {
+	// Get returns the value for key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key, expiring it after ttl (<= 0 never expires)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key if present
+	Delete(ctx context.Context, key string) error
+}
+
+// CachedExecutor runs chart queries through a Cache, so unchanged charts are
+// served without hitting the database. Entries are keyed by the config's
+// CacheKey, which covers the SQL and its bound values, so charts differing
+// only in filter values do not collide.
+//
+// Cached values keep their Go types, so a hit returns the same int64,
+// time.Time or decimal.Decimal values as the query. Results holding a type
+// the cache cannot restore are returned but not cached.
+//
+// A QueryHook may rewrite the SQL in ways the key cannot see, so an executor
+// whose Options set one runs every query uncached.
+type CachedExecutor struct {
+	db    *sqlx.DB
+	cache Cache
+	ttl   time.Duration
+
+	// Options are passed to every query; may be nil
+	Options *ExecuteOptions
+}
+
+// NewCachedExecutor returns an executor caching results in cache for ttl
+func NewCachedExecutor(db *sqlx.DB, cache Cache, ttl time.Duration) *CachedExecutor {
+	return &CachedExecutor{db: db, cache: cache, ttl: ttl}
+}
+
+// cachedRow is the serialized form of a ChartDataRow, keeping its series
+// order and the type of every value
+type cachedRow struct {
+	XValue  cachedValue            `json:"x"`
+	YValues map[string]cachedValue `json:"y"`
+	Series  []string               `json:"s,omitempty"`
+	Total   int64                  `json:"t,omitempty"`
+}
+
+// cachedValue is a scanned value tagged with its Go type. JSON alone would
+// return an int64 as a float64 and a time.Time or decimal.Decimal as a string.
+type cachedValue struct {
+	Type  string      `json:"t,omitempty"` // empty for nil, string, bool and float64, which JSON keeps
+	Value interface{} `json:"v"`
+}
+
+// newCachedValue tags v with its type, failing for a type restore cannot rebuild
+func newCachedValue(v interface{}) (cachedValue, error) {
+	switch v := v.(type) {
+	case nil, string, bool, float64:
+		return cachedValue{Value: v}, nil
+	case int64:
+		return cachedValue{Type: "int64", Value: strconv.FormatInt(v, 10)}, nil
+	case int32:
+		return cachedValue{Type: "int32", Value: strconv.FormatInt(int64(v), 10)}, nil
+	case int:
+		return cachedValue{Type: "int", Value: strconv.Itoa(v)}, nil
+	case float32:
+		return cachedValue{Type: "float32", Value: strconv.FormatFloat(float64(v), 'g', -1, 32)}, nil
+	case time.Time:
+		return cachedValue{Type: "time", Value: v.Format(time.RFC3339Nano)}, nil
+	case decimal.Decimal:
+		return cachedValue{Type: "decimal", Value: v.String()}, nil
+	default:
+		return cachedValue{}, fmt.Errorf("cannot cache a value of type %T", v)
+	}
+}
+
+// restore returns the value with its original type. Times come back with a
+// fixed UTC offset rather than their original *time.Location.
+func (c cachedValue) restore() (interface{}, error) {
+	if c.Type == "" {
+		return c.Value, nil
+	}
+	s, ok := c.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("cached %s value is not a string", c.Type)
+	}
+
+	switch c.Type {
+	case "int64":
+		n, err := strconv.ParseInt(s, 10, 64)
+		return n, err
+	case "int32":
+		n, err := strconv.ParseInt(s, 10, 32)
+		return int32(n), err
+	case "int":
+		n, err := strconv.Atoi(s)
+		return n, err
+	case "float32":
+		f, err := strconv.ParseFloat(s, 32)
+		return float32(f), err
+	case "time":
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return t, err
+	case "decimal":
+		d, err := decimal.NewFromString(s)
+		return d, err
+	default:
+		return nil, fmt.Errorf("unknown cached value type '%s'", c.Type)
+	}
+}
+
+// encodeRows serializes rows with the types of their values
+func encodeRows(rows []ChartDataRow) ([]byte, error) {
+	cached := make([]cachedRow, len(rows))
+	for i, row := range rows {
+		values, ok := row.YValues.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot cache y values of type %T", row.YValues)
+		}
+		x, err := newCachedValue(row.XValue)
+		if err != nil {
+			return nil, err
+		}
+		cached[i] = cachedRow{XValue: x, YValues: make(map[string]cachedValue, len(values)), Series: row.Series, Total: row.TotalCount}
+		for series, value := range values {
+			if cached[i].YValues[series], err = newCachedValue(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return json.Marshal(cached)
+}
+
+// decodeRows restores rows serialized by encodeRows
+func decodeRows(data []byte) ([]ChartDataRow, error) {
+	var cached []cachedRow
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+
+	rows := make([]ChartDataRow, len(cached))
+	for i, row := range cached {
+		x, err := row.XValue.restore()
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]interface{}, len(row.YValues))
+		for series, value := range row.YValues {
+			if values[series], err = value.restore(); err != nil {
+				return nil, err
+			}
+		}
+		rows[i] = ChartDataRow{XValue: x, YValues: values, Series: row.Series, TotalCount: row.Total}
+	}
+	return rows, nil
+}
+
+// Execute returns the chart's rows from the cache, or runs the query and
+// caches them. A failing cache is bypassed rather than failing the chart.
+func (e *CachedExecutor) Execute(ctx context.Context, config *ChartConfig) ([]ChartDataRow, error) {
+	if e.Options != nil && e.Options.QueryHook != nil {
+		return ExecuteChartContext(ctx, e.db, config, e.Options)
+	}
+
+	key := e.Key(config)
+
+	if data, ok, err := e.cache.Get(ctx, key); err == nil && ok {
+		// An entry that does not decode is treated as a miss and overwritten
+		if rows, err := decodeRows(data); err == nil {
+			return rows, nil
+		}
+	}
+
+	rows, err := ExecuteChartContext(ctx, e.db, config, e.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	// The rows are already in hand; a value the cache cannot keep, or a
+	// cache write failure, only costs a miss
+	if data, err := encodeRows(rows); err == nil {
+		_ = e.cache.Set(ctx, key, data, e.ttl)
+	}
+
+	return rows, nil
+}
+
+// Key returns the cache key of config, including the executor's tenant
+// override, column qualification and the options that change the scanned
+// values
+func (e *CachedExecutor) Key(config *ChartConfig) string {
+	if e.Options != nil && e.Options.Tenant != nil {
+		config = config.Clone()
+		config.Tenant = e.Options.Tenant
+	}
+	if e.Options != nil && e.Options.QualifyColumns != nil {
+		// A config that does not qualify fails in Execute before it is cached
+		if qualified, err := QualifyColumns(config, e.Options.QualifyColumns); err == nil {
+			config = qualified
+		}
+	}
+
+	key := "chatabase:" + config.CacheKey()
+	if e.Options != nil && e.Options.QualifyColumns != nil {
+		snapshot, _ := json.Marshal(e.Options.QualifyColumns)
+		sum := sha256.Sum256(snapshot)
+		key += ":schema=" + hex.EncodeToString(sum[:])
+	}
+	if e.Options != nil && e.Options.ExactDecimals {
+		key += ":decimal"
+	}
+	return key
+}
+
+// Purge removes config's cached result
+func (e *CachedExecutor) Purge(ctx context.Context, config *ChartConfig) error {
+	return e.cache.Delete(ctx, e.Key(config))
+}
+
+// PurgeKey removes a cached result by the key Key returned
+func (e *CachedExecutor) PurgeKey(ctx context.Context, key string) error {
+	return e.cache.Delete(ctx, key)
+}
+
+// LRUCache is an in-memory Cache holding at most capacity entries, evicting
+// the least recently used
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front is most recently used
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero never expires
+}
+
+// NewLRUCache returns an in-memory cache of at most capacity entries
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value for key if present and not expired
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set stores value under key, evicting the least recently used entry when full
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+// Delete removes key if present
+func (c *LRUCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package chatabase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCacheHitKeepsValueTypes(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	rows := []ChartDataRow{
+		{
+			XValue: day,
+			YValues: map[string]interface{}{
+				"count":   int64(9007199254740993),
+				"total":   decimal.RequireFromString("1234.50"),
+				"average": 12.5,
+				"small":   int32(7),
+				"ratio":   float32(0.25),
+				"region":  "north",
+				"paid":    true,
+				"empty":   nil,
+			},
+			Series:     []string{"count", "total"},
+			TotalCount: 2,
+		},
+		{XValue: "east", YValues: map[string]interface{}{"count": int64(0)}},
+	}
+
+	config := testConfig()
+	executor := NewCachedExecutor(nil, NewLRUCache(10), time.Minute)
+	data, err := encodeRows(rows)
+	if err != nil {
+		t.Fatalf("encodeRows() error = %v", err)
+	}
+	if err := executor.cache.Set(context.Background(), executor.Key(config), data, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// The executor has no database, so the rows can only come from the cache
+	cached, err := executor.Execute(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(cached) != len(rows) {
+		t.Fatalf("got %d rows, want %d", len(cached), len(rows))
+	}
+	for i := range rows {
+		want, got := rows[i], cached[i]
+		if !reflect.DeepEqual(got.Series, want.Series) || got.TotalCount != want.TotalCount {
+			t.Errorf("row %d series %v total %d, want %v and %d", i, got.Series, got.TotalCount, want.Series, want.TotalCount)
+		}
+		if reflect.TypeOf(got.XValue) != reflect.TypeOf(want.XValue) {
+			t.Errorf("row %d x is %T, want %T", i, got.XValue, want.XValue)
+		}
+		values := got.YValues.(map[string]interface{})
+		for series, value := range want.YValues.(map[string]interface{}) {
+			if reflect.TypeOf(values[series]) != reflect.TypeOf(value) {
+				t.Errorf("row %d %s is %T, want %T", i, series, values[series], value)
+			}
+		}
+	}
+
+	first := cached[0].YValues.(map[string]interface{})
+	if !cached[0].XValue.(time.Time).Equal(day) {
+		t.Errorf("x = %v, want %v", cached[0].XValue, day)
+	}
+	if first["count"] != int64(9007199254740993) {
+		t.Errorf("count = %v, want 9007199254740993", first["count"])
+	}
+	if !first["total"].(decimal.Decimal).Equal(decimal.RequireFromString("1234.5")) {
+		t.Errorf("total = %v, want 1234.50", first["total"])
+	}
+}
+
+func TestCacheSkipsUnknownValueTypes(t *testing.T) {
+	rows := []ChartDataRow{{XValue: []int{1}, YValues: map[string]interface{}{}}}
+	if _, err := encodeRows(rows); err == nil {
+		t.Error("encodeRows() accepted a value it cannot restore")
+	}
+}
+
+func TestCacheKeyCoversQualifyColumns(t *testing.T) {
+	config := testConfig()
+	config.Tables[0].Alias = ""
+	config.XAxis.Column = "region"
+	config.YAxis[0].Column = "amount"
+	config.GroupBy = []string{"region"}
+
+	columns := []ColumnInfo{{Name: "region", DataType: "text"}, {Name: "amount", DataType: "numeric"}}
+	orders := &SchemaSnapshot{Tables: []TableInfo{{Name: "orders", Columns: columns}}}
+	withNote := &SchemaSnapshot{Tables: []TableInfo{{Name: "orders", Columns: append(columns, ColumnInfo{Name: "note", DataType: "text"})}}}
+
+	keys := map[string]string{}
+	for name, snapshot := range map[string]*SchemaSnapshot{"none": nil, "orders": orders, "with note": withNote} {
+		executor := NewCachedExecutor(nil, NewLRUCache(10), time.Minute)
+		if snapshot != nil {
+			executor.Options = &ExecuteOptions{QualifyColumns: snapshot}
+		}
+		key := executor.Key(config)
+		if other, ok := keys[key]; ok {
+			t.Errorf("snapshots %q and %q share key %s", other, name, key)
+		}
+		keys[key] = name
+	}
+}
+
+func TestCachedExecutorSkipsCacheWithQueryHook(t *testing.T) {
+	config := testConfig()
+	executor := NewCachedExecutor(nil, NewLRUCache(10), time.Minute)
+	data, err := encodeRows([]ChartDataRow{{XValue: "north", YValues: map[string]interface{}{"amount": 1.0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := executor.cache.Set(context.Background(), executor.Key(config), data, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := errors.New("stop")
+	executor.Options = &ExecuteOptions{QueryHook: func(string, []interface{}) (string, []interface{}, error) {
+		return "", nil, stop
+	}}
+	if _, err := executor.Execute(context.Background(), config); !errors.Is(err, stop) {
+		t.Errorf("Execute() error = %v, want the hook error rather than a cache hit", err)
+	}
+}
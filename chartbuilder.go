@@ -0,0 +1,281 @@
+package chatabase
+
+import "strings"
+
+// ChartBuilder constructs a ChartConfig fluently:
+//
+//	config, err := NewChart("bar", "Revenue").
+//		From("orders").
+//		X("created_at").
+//		Y("amount", WithAgg("SUM")).
+//		Where("status", "=", "paid").
+//		GroupByCol("created_at").
+//		Build()
+//
+// It only assembles the existing config types; Build validates and
+// normalizes the result exactly like ValidateAndNormalizeConfig.
+type ChartBuilder struct {
+	config ChartConfig
+}
+
+// AxisOption customizes an axis added with X or Y
+type AxisOption func(*AxisConfig)
+
+// NewChart starts a chart of the given type and title
+func NewChart(chartType, title string) *ChartBuilder {
+	return &ChartBuilder{config: ChartConfig{ChartType: chartType, Title: title}}
+}
+
+// WithAgg sets the axis aggregation, e.g. "SUM"
+func WithAgg(aggregation string) AxisOption {
+	return func(a *AxisConfig) { a.Aggregation = aggregation }
+}
+
+// WithPercentile aggregates the axis as a percentile, e.g. 0.95 for p95
+func WithPercentile(percentile float64) AxisOption {
+	return func(a *AxisConfig) {
+		a.Aggregation = AggregationPercentile
+		a.Percentile = percentile
+	}
+}
+
+// WithRatio derives the series as numerator / denominator, e.g.
+// WithRatio(Operand{"signups", "SUM"}, Operand{"visits", "SUM"})
+func WithRatio(numerator, denominator Operand) AxisOption {
+	return func(a *AxisConfig) {
+		a.Numerator, a.Denominator = &numerator, &denominator
+		a.Operation = "/"
+	}
+}
+
+// WithLabel sets the axis label
+func WithLabel(label string) AxisOption {
+	return func(a *AxisConfig) { a.Label = label }
+}
+
+// WithAlias sets the result column alias of a Y series
+func WithAlias(alias string) AxisOption {
+	return func(a *AxisConfig) { a.Alias = alias }
+}
+
+// WithDataType sets the axis data type, e.g. DataTypeNumeric
+func WithDataType(dataType string) AxisOption {
+	return func(a *AxisConfig) { a.DataType = dataType }
+}
+
+// WithFormat sets the axis display format, e.g. "currency"
+func WithFormat(format string) AxisOption {
+	return func(a *AxisConfig) { a.Format = format }
+}
+
+// WithJSONPath extracts a key from a jsonb axis column
+func WithJSONPath(path ...string) AxisOption {
+	return func(a *AxisConfig) { a.JSONPath = path }
+}
+
+// WithCast converts the axis value to a SQL type, e.g. "double precision"
+func WithCast(sqlType string) AxisOption {
+	return func(a *AxisConfig) { a.Cast = sqlType }
+}
+
+// WithAxisSide places a Y series on the "left" or "right" axis
+func WithAxisSide(side string) AxisOption {
+	return func(a *AxisConfig) { a.AxisSide = side }
+}
+
+// WithNullAs replaces NULL axis values with a default, e.g. 0
+func WithNullAs(value interface{}) AxisOption {
+	return func(a *AxisConfig) { a.NullAs = value }
+}
+
+// WithSeriesFilter restricts a Y series to the rows matching a filter, taking
+// the same arguments as Where, e.g. WithSeriesFilter("status", "=", "refunded").
+// Repeated options must all match.
+func WithSeriesFilter(column, operator string, values ...interface{}) AxisOption {
+	return func(a *AxisConfig) { a.SeriesFilter = append(a.SeriesFilter, newFilter(column, operator, values)) }
+}
+
+// Description sets the chart description
+func (b *ChartBuilder) Description(description string) *ChartBuilder {
+	b.config.Description = description
+	return b
+}
+
+// From adds a table to read from
+func (b *ChartBuilder) From(table string) *ChartBuilder {
+	b.config.Tables = append(b.config.Tables, TableConfig{Name: table})
+	return b
+}
+
+// FromAs adds an aliased table to read from
+func (b *ChartBuilder) FromAs(table, alias string) *ChartBuilder {
+	b.config.Tables = append(b.config.Tables, TableConfig{Name: table, Alias: alias})
+	return b
+}
+
+// Join joins a table onto the most recently added table. alias may be empty.
+func (b *ChartBuilder) Join(joinType, table, alias, condition string) *ChartBuilder {
+	if len(b.config.Tables) == 0 {
+		// Leave the join unattached so Build reports the missing table
+		b.config.Tables = append(b.config.Tables, TableConfig{})
+	}
+	last := &b.config.Tables[len(b.config.Tables)-1]
+	last.Joins = append(last.Joins, JoinConfig{Table: table, Alias: alias, Type: joinType, Condition: condition})
+	return b
+}
+
+// ChainJoin adds a top-level join, emitted after the table joins in the order
+// declared, e.g. for a chain a -> b -> c or a self-join. alias may be empty.
+func (b *ChartBuilder) ChainJoin(joinType, table, alias, condition string) *ChartBuilder {
+	b.config.Joins = append(b.config.Joins, JoinConfig{Table: table, Alias: alias, Type: joinType, Condition: condition})
+	return b
+}
+
+// X sets the X-axis column
+func (b *ChartBuilder) X(column string, opts ...AxisOption) *ChartBuilder {
+	b.config.XAxis = AxisConfig{Column: column}
+	for _, opt := range opts {
+		opt(&b.config.XAxis)
+	}
+	return b
+}
+
+// Y adds a Y-axis series
+func (b *ChartBuilder) Y(column string, opts ...AxisOption) *ChartBuilder {
+	axis := AxisConfig{Column: column}
+	for _, opt := range opts {
+		opt(&axis)
+	}
+	b.config.YAxis = append(b.config.YAxis, axis)
+	return b
+}
+
+// Where adds a filter. A single value is bound as the filter's Value; the
+// list operators (IN, NOT IN, BETWEEN, @>, <@) take their values as Values.
+// Operators such as "IS NULL" need no value.
+func (b *ChartBuilder) Where(column, operator string, values ...interface{}) *ChartBuilder {
+	b.config.Filters = append(b.config.Filters, newFilter(column, operator, values))
+	return b
+}
+
+// WhereLast keeps rows whose column falls in the last count units, e.g.
+// WhereLast("created_at", 30, "day")
+func (b *ChartBuilder) WhereLast(column string, count int, unit string) *ChartBuilder {
+	b.config.Filters = append(b.config.Filters, FilterConfig{
+		Column:   column,
+		Relative: &RelativeDate{Count: count, Unit: unit},
+	})
+	return b
+}
+
+// Having adds a HAVING filter on an aggregate or a Y-axis alias, e.g.
+// Having("total", ">", 1000)
+func (b *ChartBuilder) Having(column, operator string, values ...interface{}) *ChartBuilder {
+	b.config.Having = append(b.config.Having, newFilter(column, operator, values))
+	return b
+}
+
+// newFilter builds a filter, placing values in Values for list operators and
+// in Value otherwise
+func newFilter(column, operator string, values []interface{}) FilterConfig {
+	filter := FilterConfig{Column: column, Operator: operator}
+	switch strings.ToUpper(operator) {
+	case "IN", "NOT IN", "BETWEEN", "@>", "<@":
+		filter.Values = values
+	default:
+		if len(values) == 1 {
+			filter.Value = values[0]
+		} else if len(values) > 1 {
+			filter.Values = values
+		}
+	}
+	return filter
+}
+
+// WhereRaw adds a raw predicate with '?' placeholders
+func (b *ChartBuilder) WhereRaw(raw string, values ...interface{}) *ChartBuilder {
+	b.config.Filters = append(b.config.Filters, FilterConfig{Raw: raw, RawValues: values})
+	return b
+}
+
+// Filter adds a fully specified filter
+func (b *ChartBuilder) Filter(filter FilterConfig) *ChartBuilder {
+	b.config.Filters = append(b.config.Filters, filter)
+	return b
+}
+
+// GroupByCol adds GROUP BY columns
+func (b *ChartBuilder) GroupByCol(columns ...string) *ChartBuilder {
+	b.config.GroupBy = append(b.config.GroupBy, columns...)
+	return b
+}
+
+// OrderBy adds an ORDER BY clause. direction may be empty for ASC.
+func (b *ChartBuilder) OrderBy(column, direction string) *ChartBuilder {
+	b.config.OrderBy = append(b.config.OrderBy, OrderConfig{Column: column, Direction: direction})
+	return b
+}
+
+// OrderByAgg orders by an aggregate that need not be selected, e.g.
+// OrderByAgg("COUNT", "", "DESC") for ORDER BY COUNT(*) DESC
+func (b *ChartBuilder) OrderByAgg(aggregation, column, direction string) *ChartBuilder {
+	b.config.OrderBy = append(b.config.OrderBy, OrderConfig{Column: column, Direction: direction, Aggregation: aggregation})
+	return b
+}
+
+// Limit sets the row limit
+func (b *ChartBuilder) Limit(limit int) *ChartBuilder {
+	b.config.Limit = limit
+	return b
+}
+
+// Options sets the chart options
+func (b *ChartBuilder) Options(options ChartOptions) *ChartBuilder {
+	b.config.Options = options
+	return b
+}
+
+// Schema qualifies every table with a schema
+func (b *ChartBuilder) Schema(schema string) *ChartBuilder {
+	b.config.Schema = schema
+	return b
+}
+
+// Dialect sets the SQL dialect, e.g. DialectMySQL
+func (b *ChartBuilder) Dialect(dialect string) *ChartBuilder {
+	b.config.Dialect = dialect
+	return b
+}
+
+// IncludeTotalCount adds a total_count column with the row count before Limit
+func (b *ChartBuilder) IncludeTotalCount() *ChartBuilder {
+	b.config.IncludeTotalCount = true
+	return b
+}
+
+// NullHandling sets how filters treat NULL, NullHandlingSmart or NullHandlingStrict
+func (b *ChartBuilder) NullHandling(mode string) *ChartBuilder {
+	b.config.NullHandling = mode
+	return b
+}
+
+// AllowTables limits the tables the chart may read, see ChartConfig.TableAllowlist
+func (b *ChartBuilder) AllowTables(tables ...string) *ChartBuilder {
+	b.config.TableAllowlist = append(b.config.TableAllowlist, tables...)
+	return b
+}
+
+// Tenant restricts the chart to one tenant's rows
+func (b *ChartBuilder) Tenant(column string, value interface{}) *ChartBuilder {
+	b.config.Tenant = &TenantFilter{Column: column, Value: value}
+	return b
+}
+
+// Build validates and normalizes the configuration and returns it
+func (b *ChartBuilder) Build() (*ChartConfig, error) {
+	config := b.config.Clone()
+	if err := ValidateAndNormalizeConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -1,90 +1,569 @@
 package chatabase
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
 )
 
 type ChartConfig struct {
+	// Version of the config shape, see MigrateConfig
+	SchemaVersion int `json:"schema_version,omitempty" yaml:"schema_version,omitempty" toml:"schema_version,omitempty"`
+
 	// Chart basics
-	ChartType   string `json:"chart_type"` // "line", "bar", "pie", "scatter", "area", "histogram"
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	ChartType   string `json:"chart_type" yaml:"chart_type" toml:"chart_type"` // "line", "bar", "pie", "scatter", "area", "histogram"
+	Title       string `json:"title" yaml:"title" toml:"title"`
+	Description string `json:"description" yaml:"description" toml:"description"`
 
 	// Data source
-	Tables []TableConfig `json:"tables"`
+	Tables []TableConfig `json:"tables" yaml:"tables" toml:"tables"`
+	Schema string        `json:"schema,omitempty" yaml:"schema,omitempty" toml:"schema,omitempty"` // Qualifies every table as "schema".table; empty uses the search_path
+
+	// Joins are emitted in declared order after the first table's joins, so
+	// a chain a -> b -> c or a self-join (same table, another alias) is
+	// spelled out exactly, independently of Tables
+	Joins []JoinConfig `json:"joins,omitempty" yaml:"joins,omitempty" toml:"joins,omitempty"`
+
+	// SQL dialect to generate: "postgres" (default), "mysql", "sqlite", "clickhouse", "bigquery"
+	Dialect string `json:"dialect,omitempty" yaml:"dialect,omitempty" toml:"dialect,omitempty"`
 
 	// Axes configuration
-	XAxis AxisConfig   `json:"x_axis"`
-	YAxis []AxisConfig `json:"y_axis"` // Array to support multiple Y series
+	XAxis AxisConfig   `json:"x_axis" yaml:"x_axis" toml:"x_axis"`
+	YAxis []AxisConfig `json:"y_axis" yaml:"y_axis" toml:"y_axis"` // Array to support multiple Y series
 
 	// Aggregation and grouping
-	GroupBy []string       `json:"group_by"`
-	Filters []FilterConfig `json:"filters"`
+	GroupBy []string `json:"group_by" yaml:"group_by" toml:"group_by"`
+
+	// GroupByMode adds subtotal rows: "plain" (default), "rollup", "cube" or
+	// "grouping_sets" with the sets in GroupingSets. Postgres supports all
+	// modes, MySQL only rollup (GROUP BY ... WITH ROLLUP), SQLite none.
+	GroupByMode  string     `json:"group_by_mode,omitempty" yaml:"group_by_mode,omitempty" toml:"group_by_mode,omitempty"`
+	GroupingSets [][]string `json:"grouping_sets,omitempty" yaml:"grouping_sets,omitempty" toml:"grouping_sets,omitempty"`
+
+	Filters []FilterConfig `json:"filters" yaml:"filters" toml:"filters"`
+	Having  []FilterConfig `json:"having,omitempty" yaml:"having,omitempty" toml:"having,omitempty"` // Filters on aggregates; Column may name a Y-axis alias
+
+	// NullHandling selects how filters treat NULL: "smart" (default) rewrites
+	// NULL and boolean comparisons, e.g. = true to IS TRUE and IN with a NULL
+	// to IN (...) OR column IS NULL; "strict" emits the operators as written
+	// and leaves NULLs to SQL's three-valued logic.
+	NullHandling string `json:"null_handling,omitempty" yaml:"null_handling,omitempty" toml:"null_handling,omitempty"`
 
 	// Chart-specific options
-	Options ChartOptions `json:"options"`
+	Options ChartOptions `json:"options" yaml:"options" toml:"options"`
+
+	// DistinctOn keeps the first row per distinct value of these columns, as
+	// ordered by OrderBy, which must lead with them. Postgres only.
+	DistinctOn []string `json:"distinct_on,omitempty" yaml:"distinct_on,omitempty" toml:"distinct_on,omitempty"`
 
 	// Query limits
-	Limit   int           `json:"limit"`
-	OrderBy []OrderConfig `json:"order_by"`
+	Limit   int           `json:"limit" yaml:"limit" toml:"limit"`
+	OrderBy []OrderConfig `json:"order_by" yaml:"order_by" toml:"order_by"`
+
+	// WithTies keeps rows tied with the last of Limit rows, emitting
+	// FETCH FIRST n ROWS WITH TIES instead of LIMIT. It requires OrderBy and
+	// is only supported by the postgres dialect (PostgreSQL 13 or later).
+	WithTies bool `json:"with_ties,omitempty" yaml:"with_ties,omitempty" toml:"with_ties,omitempty"`
+
+	// IncludeTotalCount adds a total_count column, COUNT(*) OVER (), holding
+	// the number of result rows before Limit, so a paginated table needs no
+	// second COUNT query. Scanned rows carry it in ChartDataRow.TotalCount.
+	IncludeTotalCount bool `json:"include_total_count,omitempty" yaml:"include_total_count,omitempty" toml:"include_total_count,omitempty"`
+
+	// Tenant restricts every query to one tenant's rows. It is never read from
+	// JSON or YAML so an untrusted config cannot choose its own tenant.
+	Tenant *TenantFilter `json:"-" yaml:"-" toml:"-"`
+
+	// TableAllowlist, when set, limits the tables a config may read, e.g.
+	// "orders" or "analytics.orders"; validation rejects any other. Like
+	// Tenant, it is never read from JSON or YAML.
+	TableAllowlist []string `json:"-" yaml:"-" toml:"-"`
 }
 
+// TotalCountColumn is the result column ChartConfig.IncludeTotalCount adds
+const TotalCountColumn = "total_count"
+
+// NULL handling strategies for ChartConfig.NullHandling
+const (
+	NullHandlingSmart  = "smart"
+	NullHandlingStrict = "strict"
+)
+
+// TenantFilter ANDs "column = value" on the primary table into the WHERE clause
+type TenantFilter struct {
+	Column string      // e.g. "tenant_id"; qualified with the primary table alias when bare
+	Value  interface{} // bound as a parameter
+}
+
+// TableConfig is a table the chart reads. The first table is the one in
+// FROM and carries every join; further tables are listed after it as comma
+// tables, joined by the filters.
 type TableConfig struct {
-	Name  string       `json:"name"`
-	Alias string       `json:"alias,omitempty"`
-	Joins []JoinConfig `json:"joins,omitempty"`
+	Name     string       `json:"name" yaml:"name" toml:"name"`
+	Alias    string       `json:"alias,omitempty" yaml:"alias,omitempty" toml:"alias,omitempty"`
+	Database string       `json:"database,omitempty" yaml:"database,omitempty" toml:"database,omitempty"` // MySQL database (Postgres schema) the table lives in
+	Joins    []JoinConfig `json:"joins,omitempty" yaml:"joins,omitempty" toml:"joins,omitempty"`
+
+	// Sample reads a random sample of the table instead of all its rows.
+	// PostgreSQL only; applies to the first table, the one in FROM.
+	Sample *TableSample `json:"sample,omitempty" yaml:"sample,omitempty" toml:"sample,omitempty"`
+}
+
+// TableSample emits TABLESAMPLE METHOD (percent) for quick approximate
+// previews of large tables. SYSTEM samples whole pages and is fastest;
+// BERNOULLI samples individual rows and is more representative.
+type TableSample struct {
+	Method  string  `json:"method" yaml:"method" toml:"method"`    // "SYSTEM", "BERNOULLI"
+	Percent float64 `json:"percent" yaml:"percent" toml:"percent"` // Share of rows to read, greater than 0 and at most 100
 }
 
+// Table sampling methods for TableSample.Method
+const (
+	SampleSystem    = "SYSTEM"
+	SampleBernoulli = "BERNOULLI"
+)
+
 type JoinConfig struct {
-	Table     string `json:"table"`
-	Alias     string `json:"alias,omitempty"`
-	Type      string `json:"type"`      // "INNER", "LEFT", "RIGHT", "FULL"
-	Condition string `json:"condition"` // "users.id = orders.user_id"
+	Table     string `json:"table" yaml:"table" toml:"table"`
+	Alias     string `json:"alias,omitempty" yaml:"alias,omitempty" toml:"alias,omitempty"`
+	Database  string `json:"database,omitempty" yaml:"database,omitempty" toml:"database,omitempty"` // MySQL database (Postgres schema) the table lives in
+	Type      string `json:"type" yaml:"type" toml:"type"`                                           // "INNER", "LEFT", "RIGHT", "FULL"
+	Condition string `json:"condition" yaml:"condition" toml:"condition"`                            // "users.id = orders.user_id"
 }
 
+// AggregationPercent aggregates a Y axis as its percentage of the total SUM
+// across all groups, for pie and 100%-stacked charts
+const AggregationPercent = "PCT"
+
+// AggregationMedian aggregates a numeric axis as its median,
+// percentile_cont(0.5) WITHIN GROUP (ORDER BY column). Postgres only.
+const AggregationMedian = "MEDIAN"
+
+// AggregationPercentile aggregates a numeric axis as the continuous
+// percentile in AxisConfig.Percentile, e.g. 0.95 for p95 latency:
+// percentile_cont(0.95) WITHIN GROUP (ORDER BY column). Postgres only.
+const AggregationPercentile = "PERCENTILE"
+
+// Categorical aggregations. STRING_AGG joins the values with
+// AxisConfig.Delimiter, string_agg(tag, $1) (group_concat on SQLite);
+// MODE picks the most frequent value, mode() WITHIN GROUP (ORDER BY
+// category), and is Postgres only.
+const (
+	AggregationStringAgg = "STRING_AGG"
+	AggregationMode      = "MODE"
+)
+
+// DefaultStringAggDelimiter joins STRING_AGG values when no delimiter is set
+const DefaultStringAggDelimiter = ", "
+
 type AxisConfig struct {
-	Column      string `json:"column"`           // "created_at", "amount", "COUNT(*)"
-	Label       string `json:"label"`            // Human-readable label
-	Aggregation string `json:"aggregation"`      // "SUM", "COUNT", "AVG", "MIN", "MAX"
-	DataType    string `json:"data_type"`        // "numeric", "datetime", "string"
-	Format      string `json:"format,omitempty"` // "currency", "percentage", "date"
-	Alias       string `json:"alias,omitempty"`  // NEW
+	Column      string `json:"column" yaml:"column" toml:"column"`                               // "created_at", "amount", "COUNT(*)"; empty or "*" counts rows with COUNT
+	Label       string `json:"label" yaml:"label" toml:"label"`                                  // Human-readable label
+	Aggregation string `json:"aggregation" yaml:"aggregation" toml:"aggregation"`                // "SUM", "COUNT", "AVG", "MIN", "MAX", "STDDEV", "VARIANCE", "MEDIAN", "PERCENTILE", "STRING_AGG", "MODE", "PCT"
+	DataType    string `json:"data_type" yaml:"data_type" toml:"data_type"`                      // "numeric", "datetime", "string", "boolean"
+	Format      string `json:"format,omitempty" yaml:"format,omitempty" toml:"format,omitempty"` // "currency", "percentage", "date"
+	Alias       string `json:"alias,omitempty" yaml:"alias,omitempty" toml:"alias,omitempty"`    // NEW
+
+	// Percentile is the fraction, between 0 and 1, computed by the PERCENTILE
+	// aggregation: 0.5 is the median, 0.99 the p99
+	Percentile float64 `json:"percentile,omitempty" yaml:"percentile,omitempty" toml:"percentile,omitempty"`
+
+	// Delimiter joins the values of the STRING_AGG aggregation; empty uses
+	// DefaultStringAggDelimiter. It is bound as a parameter.
+	Delimiter string `json:"delimiter,omitempty" yaml:"delimiter,omitempty" toml:"delimiter,omitempty"`
+
+	// JSONPath extracts a key from a jsonb column: ["source"] -> data->>'source',
+	// ["a", "b"] -> data#>>'{"a","b"}'. Column must then be a plain column name.
+	JSONPath []string `json:"json_path,omitempty" yaml:"json_path,omitempty" toml:"json_path,omitempty"`
+
+	// NullAs replaces NULL results with a default, e.g. 0 so missing
+	// aggregates render as zero: COALESCE(SUM(amount), $1)
+	NullAs interface{} `json:"null_as,omitempty" yaml:"null_as,omitempty" toml:"null_as,omitempty"`
+
+	// Cast converts the selected value to a SQL type, e.g. "double precision"
+	// so a Postgres numeric aggregate scans as float64 rather than a string
+	Cast string `json:"cast,omitempty" yaml:"cast,omitempty" toml:"cast,omitempty"`
+
+	// AxisSide places a Y series on the "left" (default) or "right" axis of a
+	// combo chart. It is renderer metadata and does not change the SQL.
+	AxisSide string `json:"axis_side,omitempty" yaml:"axis_side,omitempty" toml:"axis_side,omitempty"`
+
+	// Numerator and Denominator derive a Y series from two operands combined
+	// by Operation, e.g. a conversion rate SUM(signups) / SUM(visits). Division
+	// is done in floating point and yields NULL when the denominator is zero.
+	// Column and Aggregation must be empty.
+	Numerator   *Operand `json:"numerator,omitempty" yaml:"numerator,omitempty" toml:"numerator,omitempty"`
+	Denominator *Operand `json:"denominator,omitempty" yaml:"denominator,omitempty" toml:"denominator,omitempty"`
+	Operation   string   `json:"operation,omitempty" yaml:"operation,omitempty" toml:"operation,omitempty"` // "/" (default), "*", "+", "-"
+
+	// SeriesFilter restricts an aggregated Y series to the rows matching all
+	// of its filters, e.g. SUM(amount) FILTER (WHERE status = 'refunded'),
+	// while the other series aggregate every row. It takes the same filters
+	// as ChartConfig.Filters, which still apply to every series first.
+	SeriesFilter []FilterConfig `json:"series_filter,omitempty" yaml:"series_filter,omitempty" toml:"series_filter,omitempty"`
+}
+
+// Operand is one side of a derived series: a column and its aggregation
+type Operand struct {
+	Column      string `json:"column" yaml:"column" toml:"column"`
+	Aggregation string `json:"aggregation,omitempty" yaml:"aggregation,omitempty" toml:"aggregation,omitempty"` // "SUM", "COUNT", "AVG", "MIN", "MAX"
+}
+
+// aggregated reports whether the axis selects an aggregate
+func (a AxisConfig) aggregated() bool {
+	if a.Numerator != nil && a.Denominator != nil {
+		return a.Numerator.Aggregation != "" || a.Denominator.Aggregation != ""
+	}
+	return a.Aggregation != ""
 }
 
 type FilterConfig struct {
-	Column    string        `json:"column"`
-	Operator  string        `json:"operator"` // "=", "!=", ">", "<", ">=", "<=", "IN", "LIKE", "BETWEEN"
-	Value     interface{}   `json:"value"`
-	Values    []interface{} `json:"values,omitempty"` // For IN operator
-	Raw       string        // NEW: if set, use as-is (with placeholders)
-	RawValues []interface{} // NEW: bind params for Raw
+	Column   string          `json:"column" yaml:"column" toml:"column"`
+	Operator string          `json:"operator" yaml:"operator" toml:"operator"` // "=", "!=", ">", "<", ">=", "<=", "IN", "NOT IN", "LIKE", "BETWEEN", "IS", "IS NOT", "IS NULL", "IS NOT NULL", "@>", "<@", "ANY"
+	Value    interface{}     `json:"value" yaml:"value" toml:"value"`
+	Values   []interface{}   `json:"values,omitempty" yaml:"values,omitempty" toml:"values,omitempty"`          // For IN operator
+	Columns  []string        `json:"columns,omitempty" yaml:"columns,omitempty" toml:"columns,omitempty"`       // For multi-column (tuple) IN: "(country, plan) IN (...)"
+	Tuples   [][]interface{} `json:"tuples,omitempty" yaml:"tuples,omitempty" toml:"tuples,omitempty"`          // Value tuples for multi-column IN, one entry per row
+	JSONPath []string        `json:"json_path,omitempty" yaml:"json_path,omitempty" toml:"json_path,omitempty"` // Filter on a jsonb key instead of the whole column
+
+	// Subquery operand for "op ANY"/"op ALL" operators, e.g. "> ALL" with
+	// "SELECT threshold FROM limits WHERE region = ?", and for EXISTS /
+	// NOT EXISTS, which take no column. Placeholders are '?' or $1..$n
+	// relative to SubqueryArgs and are renumbered into the query.
+	Subquery     string        `json:"subquery,omitempty" yaml:"subquery,omitempty" toml:"subquery,omitempty"`
+	SubqueryArgs []interface{} `json:"subquery_args,omitempty" yaml:"subquery_args,omitempty" toml:"subquery_args,omitempty"`
+
+	// ValueExpr is a trusted SQL expression compared against the column in
+	// place of a bound Value, e.g. "now() - interval '7 days'". Only the
+	// comparison operators take it, and it must be a self-contained expression
+	// without placeholders. It is emitted in parentheses as the right-hand side.
+	ValueExpr string `json:"value_expr,omitempty" yaml:"value_expr,omitempty" toml:"value_expr,omitempty"`
+
+	// Relative compares the column with a window around the current time,
+	// e.g. the last 30 days, in place of an operator and value
+	Relative *RelativeDate `json:"relative,omitempty" yaml:"relative,omitempty" toml:"relative,omitempty"`
+
+	Raw       string        `json:"raw,omitempty" yaml:"raw,omitempty" toml:"raw,omitempty"`                      // NEW: if set, use as-is (with placeholders)
+	RawValues []interface{} `json:"raw_values,omitempty" yaml:"raw_values,omitempty" toml:"raw_values,omitempty"` // NEW: bind params for Raw
 }
 
 type OrderConfig struct {
-	Column    string `json:"column"`
-	Direction string `json:"direction"` // "ASC", "DESC"
+	Column    string `json:"column" yaml:"column" toml:"column"`
+	Direction string `json:"direction" yaml:"direction" toml:"direction"` // "ASC", "DESC"
+
+	// Aggregation orders by an aggregate of Column that need not be
+	// selected, e.g. COUNT with an empty column for ORDER BY COUNT(*) DESC.
+	// Requires group_by.
+	Aggregation string `json:"aggregation,omitempty" yaml:"aggregation,omitempty" toml:"aggregation,omitempty"` // "SUM", "COUNT", "AVG", "MIN", "MAX"
+}
+
+// orderExpr returns the expression an order clause sorts by
+func (o OrderConfig) orderExpr() string {
+	if o.Aggregation == "" {
+		return o.Column
+	}
+	return aggregateExpr(nil, AxisConfig{Aggregation: o.Aggregation}, o.Column)
 }
 
 type ChartOptions struct {
 	// Visual options
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
-	Theme  string `json:"theme"`
+	Width  int    `json:"width" yaml:"width" toml:"width"`
+	Height int    `json:"height" yaml:"height" toml:"height"`
+	Theme  string `json:"theme" yaml:"theme" toml:"theme"`
 
 	// Chart-specific
-	Stacked    bool `json:"stacked,omitempty"` // For bar/area charts
-	ShowLegend bool `json:"show_legend"`
-	ShowGrid   bool `json:"show_grid"`
+	Stacked    bool `json:"stacked,omitempty" yaml:"stacked,omitempty" toml:"stacked,omitempty"` // For bar/area charts
+	ShowLegend bool `json:"show_legend" yaml:"show_legend" toml:"show_legend"`
+	ShowGrid   bool `json:"show_grid" yaml:"show_grid" toml:"show_grid"`
 
 	// Date/time specific
-	DateFormat   string `json:"date_format,omitempty"`
-	TimeInterval string `json:"time_interval,omitempty"` // "day", "week", "month", "year"
+	DateFormat   string   `json:"date_format,omitempty" yaml:"date_format,omitempty" toml:"date_format,omitempty"`
+	TimeInterval string   `json:"time_interval,omitempty" yaml:"time_interval,omitempty" toml:"time_interval,omitempty"` // "day", "week", "month", "year", "iso_week", "fiscal_quarter"
+	GapFill      *GapFill `json:"gap_fill,omitempty" yaml:"gap_fill,omitempty" toml:"gap_fill,omitempty"`                // Emit every time bucket, see GapFill
+	Timezone     string   `json:"timezone,omitempty" yaml:"timezone,omitempty" toml:"timezone,omitempty"`                // IANA zone the time buckets are cut in, e.g. "America/New_York"; requires GapFill
+
+	// FiscalYearStartMonth is the month (1-12) the fiscal year starts in, for
+	// the "fiscal_quarter" time interval; 0 means January
+	FiscalYearStartMonth int `json:"fiscal_year_start_month,omitempty" yaml:"fiscal_year_start_month,omitempty" toml:"fiscal_year_start_month,omitempty"`
+
+	// Colors, assigned to Y series in order, or by series alias in
+	// SeriesColors which takes precedence. See ColorForSeries.
+	Colors       []string          `json:"colors,omitempty" yaml:"colors,omitempty" toml:"colors,omitempty"`
+	SeriesColors map[string]string `json:"series_colors,omitempty" yaml:"series_colors,omitempty" toml:"series_colors,omitempty"`
+
+	// Observability
+	AnnotateSQL bool `json:"annotate_sql,omitempty" yaml:"annotate_sql,omitempty" toml:"annotate_sql,omitempty"` // Prefix the SQL with a comment naming the chart
+}
+
+// Grouping modes for ChartConfig.GroupByMode
+const (
+	GroupByModePlain        = "plain"
+	GroupByModeRollup       = "rollup"
+	GroupByModeCube         = "cube"
+	GroupByModeGroupingSets = "grouping_sets"
+)
+
+// Y-axis sides for combo charts
+const (
+	AxisSideLeft  = "left"
+	AxisSideRight = "right"
+)
+
+// ChartTypeScatter plots one point per row of raw, numeric X/Y pairs
+const ChartTypeScatter = "scatter"
+
+// DefaultScatterLimit samples scatter charts that set no limit
+const DefaultScatterLimit = 1000
+
+// Values accepted by validation, e.g. for building dropdowns. ChartConfigSchema
+// publishes the same lists. Callers must not modify them.
+var (
+	ValidChartTypes      = []string{"line", "bar", "pie", ChartTypeScatter, "area", "histogram"}
+	ValidJoinTypes       = []string{"INNER", "LEFT", "RIGHT", "FULL"}
+	ValidOperators       = []string{"=", "!=", ">", "<", ">=", "<=", "IN", "NOT IN", "LIKE", "BETWEEN", "IS", "IS NOT", "IS NULL", "IS NOT NULL", "@>", "<@", "ANY"}
+	ValidAggregations    = []string{"SUM", "COUNT", "AVG", "MIN", "MAX", "STDDEV", "VARIANCE", AggregationMedian, AggregationPercentile, AggregationStringAgg, AggregationMode, AggregationPercent}
+	ValidOrderDirections = []string{"ASC", "DESC"}
+	ValidAxisSides       = []string{AxisSideLeft, AxisSideRight}
+	ValidGroupByModes    = []string{GroupByModePlain, GroupByModeRollup, GroupByModeCube, GroupByModeGroupingSets}
+	ValidOperations      = []string{"/", "*", "+", "-"}
+	ValidSampleMethods   = []string{SampleSystem, SampleBernoulli}
+	ValidNullHandling    = []string{NullHandlingSmart, NullHandlingStrict}
+)
+
+// UnmarshalJSON decodes a ChartConfig, additionally accepting a single object
+// (or string) in place of the tables, y_axis and group_by arrays so older and
+// hand-written configs parse
+func (c *ChartConfig) UnmarshalJSON(data []byte) error {
+	type plainConfig ChartConfig
+	raw := struct {
+		*plainConfig
+		Tables  json.RawMessage `json:"tables"`
+		YAxis   json.RawMessage `json:"y_axis"`
+		GroupBy json.RawMessage `json:"group_by"`
+	}{plainConfig: (*plainConfig)(c)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if err := unmarshalOneOrMany(raw.Tables, &c.Tables); err != nil {
+		return fmt.Errorf("tables: %w", err)
+	}
+	if err := unmarshalOneOrMany(raw.YAxis, &c.YAxis); err != nil {
+		return fmt.Errorf("y_axis: %w", err)
+	}
+	if err := unmarshalOneOrMany(raw.GroupBy, &c.GroupBy); err != nil {
+		return fmt.Errorf("group_by: %w", err)
+	}
+
+	return nil
+}
+
+// unmarshalOneOrMany decodes either a JSON array or a single value into out
+func unmarshalOneOrMany[T any](data json.RawMessage, out *[]T) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+
+	if trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, out)
+	}
+
+	var single T
+	if err := json.Unmarshal(trimmed, &single); err != nil {
+		return err
+	}
+	*out = []T{single}
+	return nil
+}
+
+// oneOrManyKeys are the fields that accept a single value in place of an array
+var oneOrManyKeys = []string{"tables", "y_axis", "group_by"}
+
+// UnmarshalYAML decodes a ChartConfig, accepting a single mapping (or string)
+// in place of the tables, y_axis and group_by sequences as UnmarshalJSON does
+func (c *ChartConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plainConfig ChartConfig
+	if value.Kind != yaml.MappingNode {
+		return value.Decode((*plainConfig)(c))
+	}
+
+	node := *value
+	node.Content = append([]*yaml.Node{}, value.Content...)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		item := node.Content[i+1]
+		resolved := item
+		if item.Kind == yaml.AliasNode {
+			resolved = item.Alias
+		}
+		if contains(oneOrManyKeys, node.Content[i].Value) && resolved.Kind != yaml.SequenceNode && resolved.Tag != "!!null" {
+			node.Content[i+1] = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{item}}
+		}
+	}
+	return node.Decode((*plainConfig)(c))
+}
 
-	// Colors
-	Colors []string `json:"colors,omitempty"`
+// UnmarshalTOML decodes a ChartConfig, accepting a single table (or string)
+// in place of the tables, y_axis and group_by arrays as UnmarshalJSON does
+func (c *ChartConfig) UnmarshalTOML(data interface{}) error {
+	type plainConfig ChartConfig
+	fields, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("chart config must be a TOML table, got %T", data)
+	}
+
+	normalized := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if contains(oneOrManyKeys, key) {
+			switch value.(type) {
+			case []interface{}, []map[string]interface{}:
+			default:
+				value = []interface{}{value}
+			}
+		}
+		normalized[key] = value
+	}
+
+	// Re-encode the normalized tables so the struct tags drive decoding
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(normalized); err != nil {
+		return err
+	}
+	_, err := toml.Decode(buf.String(), (*plainConfig)(c))
+	return err
+}
+
+// Clone returns a deep copy of the configuration. Slices are copied so
+// that mutating the clone never affects the original; individual filter
+// values are copied as-is.
+func (c *ChartConfig) Clone() *ChartConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+
+	if c.Tables != nil {
+		clone.Tables = make([]TableConfig, len(c.Tables))
+		for i, table := range c.Tables {
+			table.Joins = cloneSlice(table.Joins)
+			if table.Sample != nil {
+				sample := *table.Sample
+				table.Sample = &sample
+			}
+			clone.Tables[i] = table
+		}
+	}
+	clone.Joins = cloneSlice(c.Joins)
+
+	clone.XAxis = c.XAxis.clone()
+	if c.YAxis != nil {
+		clone.YAxis = make([]AxisConfig, len(c.YAxis))
+		for i, axis := range c.YAxis {
+			clone.YAxis[i] = axis.clone()
+		}
+	}
+
+	clone.GroupBy = cloneSlice(c.GroupBy)
+	if c.GroupingSets != nil {
+		clone.GroupingSets = make([][]string, len(c.GroupingSets))
+		for i, set := range c.GroupingSets {
+			clone.GroupingSets[i] = cloneSlice(set)
+		}
+	}
+	if c.Filters != nil {
+		clone.Filters = make([]FilterConfig, len(c.Filters))
+		for i, filter := range c.Filters {
+			clone.Filters[i] = filter.clone()
+		}
+	}
+
+	if c.Having != nil {
+		clone.Having = make([]FilterConfig, len(c.Having))
+		for i, filter := range c.Having {
+			clone.Having[i] = filter.clone()
+		}
+	}
+
+	clone.Options.Colors = cloneSlice(c.Options.Colors)
+	if c.Options.SeriesColors != nil {
+		clone.Options.SeriesColors = make(map[string]string, len(c.Options.SeriesColors))
+		for alias, color := range c.Options.SeriesColors {
+			clone.Options.SeriesColors[alias] = color
+		}
+	}
+	if c.Options.GapFill != nil {
+		gapFill := *c.Options.GapFill
+		clone.Options.GapFill = &gapFill
+	}
+	clone.OrderBy = cloneSlice(c.OrderBy)
+	clone.DistinctOn = cloneSlice(c.DistinctOn)
+	clone.TableAllowlist = cloneSlice(c.TableAllowlist)
+
+	if c.Tenant != nil {
+		tenant := *c.Tenant
+		clone.Tenant = &tenant
+	}
+
+	return &clone
+}
+
+// clone returns a deep copy of the axis
+func (a AxisConfig) clone() AxisConfig {
+	a.JSONPath = cloneSlice(a.JSONPath)
+	if a.Numerator != nil {
+		numerator := *a.Numerator
+		a.Numerator = &numerator
+	}
+	if a.Denominator != nil {
+		denominator := *a.Denominator
+		a.Denominator = &denominator
+	}
+	if a.SeriesFilter != nil {
+		filters := make([]FilterConfig, len(a.SeriesFilter))
+		for i, filter := range a.SeriesFilter {
+			filters[i] = filter.clone()
+		}
+		a.SeriesFilter = filters
+	}
+	return a
+}
+
+// clone returns a deep copy of the filter
+func (f FilterConfig) clone() FilterConfig {
+	f.Values = cloneSlice(f.Values)
+	f.Columns = cloneSlice(f.Columns)
+	if f.Tuples != nil {
+		tuples := make([][]interface{}, len(f.Tuples))
+		for i, tuple := range f.Tuples {
+			tuples[i] = cloneSlice(tuple)
+		}
+		f.Tuples = tuples
+	}
+	f.JSONPath = cloneSlice(f.JSONPath)
+	f.SubqueryArgs = cloneSlice(f.SubqueryArgs)
+	f.RawValues = cloneSlice(f.RawValues)
+	if f.Relative != nil {
+		relative := *f.Relative
+		f.Relative = &relative
+	}
+	return f
+}
+
+// cloneSlice copies a slice, preserving nil
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
 }
 
 // ParseMultipleConfigs parses multiple chart configurations from a JSON array string
@@ -118,6 +597,16 @@ func ValidateAndNormalizeConfig(config *ChartConfig) error {
 	// Normalize chart type to lowercase
 	config.ChartType = strings.ToLower(config.ChartType)
 
+	// Stamp the current schema version
+	if config.SchemaVersion == 0 {
+		config.SchemaVersion = CurrentSchemaVersion
+	}
+
+	// Sample scatter charts, which select one point per row
+	if config.ChartType == ChartTypeScatter && config.Limit == 0 {
+		config.Limit = DefaultScatterLimit
+	}
+
 	// Set default join type if not specified
 	for i := range config.Tables {
 		for j := range config.Tables[i].Joins {
@@ -126,12 +615,18 @@ func ValidateAndNormalizeConfig(config *ChartConfig) error {
 			}
 		}
 	}
+	for i := range config.Joins {
+		if config.Joins[i].Type == "" {
+			config.Joins[i].Type = "INNER"
+		}
+	}
 
 	// Set default order direction if not specified
 	for i := range config.OrderBy {
 		if config.OrderBy[i].Direction == "" {
 			config.OrderBy[i].Direction = "ASC"
 		}
+		config.OrderBy[i].Direction = strings.ToUpper(config.OrderBy[i].Direction)
 	}
 
 	// Set default options
@@ -193,6 +688,16 @@ func UnmarshalChartConfigs(jsonStr string) ([]*ChartConfig, error) {
 	return configs, nil
 }
 
+// Validate checks the configuration without normalizing it, e.g. for configs
+// built in code rather than parsed. It applies the same rules as
+// ValidateAndNormalizeConfig.
+func (c *ChartConfig) Validate() error {
+	if c == nil {
+		return fmt.Errorf("chart config is nil")
+	}
+	return validateChartConfig(c)
+}
+
 // validateChartConfig validates the chart configuration
 func validateChartConfig(config *ChartConfig) error {
 	// Check required fields
@@ -212,11 +717,27 @@ func validateChartConfig(config *ChartConfig) error {
 		return fmt.Errorf("at least one y-axis is required")
 	}
 
+	if config.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("schema_version %d is newer than supported version %d", config.SchemaVersion, CurrentSchemaVersion)
+	}
+
 	// Validate chart type
-	validChartTypes := []string{"line", "bar", "pie", "scatter", "area", "histogram"}
-	if !contains(validChartTypes, config.ChartType) {
+	if !contains(ValidChartTypes, config.ChartType) {
 		return fmt.Errorf("invalid chart_type: %s. Must be one of: %s",
-			config.ChartType, strings.Join(validChartTypes, ", "))
+			config.ChartType, strings.Join(ValidChartTypes, ", "))
+	}
+
+	// Validate dialect
+	if config.Dialect != "" && !contains(ValidDialects, config.Dialect) {
+		return fmt.Errorf("invalid dialect: %s. Must be one of: %s",
+			config.Dialect, strings.Join(ValidDialects, ", "))
+	}
+	dialect := dialectOf(config)
+
+	// Validate NULL handling
+	if config.NullHandling != "" && !contains(ValidNullHandling, config.NullHandling) {
+		return fmt.Errorf("invalid null_handling '%s'. Must be one of: %s",
+			config.NullHandling, strings.Join(ValidNullHandling, ", "))
 	}
 
 	// Validate table configurations
@@ -225,131 +746,1062 @@ func validateChartConfig(config *ChartConfig) error {
 			return fmt.Errorf("table name is required at index %d", i)
 		}
 
-		// Validate joins
+		if table.Database != "" && !supportsDatabase(dialect) {
+			return fmt.Errorf("database qualification at table index %d is not supported by the %s dialect", i, dialect)
+		}
+
+		if table.Sample != nil {
+			if err := validateTableSample(table.Sample, i, dialect); err != nil {
+				return err
+			}
+		}
+
+		// Validate joins. They all belong to the first table, the one in FROM;
+		// further tables are comma tables and cannot carry joins.
+		if i > 0 && len(table.Joins) > 0 {
+			return fmt.Errorf("joins at table index %d must be declared on the first table; further tables are joined with a comma and filters", i)
+		}
 		for j, join := range table.Joins {
-			if err := validateJoinConfig(&join, i, j); err != nil {
+			if err := validateJoinConfig(&join, fmt.Sprintf("table index %d, join index %d", i, j)); err != nil {
 				return err
 			}
+			if join.Database != "" && !supportsDatabase(dialect) {
+				return fmt.Errorf("database qualification at table index %d, join index %d is not supported by the %s dialect", i, j, dialect)
+			}
+		}
+	}
+	for i, join := range config.Joins {
+		if err := validateJoinConfig(&join, fmt.Sprintf("joins index %d", i)); err != nil {
+			return err
 		}
+		if join.Database != "" && !supportsDatabase(dialect) {
+			return fmt.Errorf("database qualification at joins index %d is not supported by the %s dialect", i, dialect)
+		}
+	}
+	if err := validateTableAliases(config); err != nil {
+		return err
 	}
 
 	// Validate X-axis
 	if config.XAxis.Column == "" {
 		return fmt.Errorf("x_axis column is required")
 	}
+	if len(config.XAxis.JSONPath) > 0 && !isColumnName(config.XAxis.Column) {
+		return fmt.Errorf("x_axis json_path requires a plain column name, got '%s'", config.XAxis.Column)
+	}
+	if len(config.XAxis.JSONPath) > 0 && dialect != DialectPostgres {
+		return fmt.Errorf("x_axis json_path is only supported by the postgres dialect")
+	}
+	if config.XAxis.Cast != "" && !isTypeName(config.XAxis.Cast) {
+		return fmt.Errorf("invalid cast '%s' for x_axis", config.XAxis.Cast)
+	}
+	if config.XAxis.AxisSide != "" {
+		return fmt.Errorf("axis_side applies only to y_axis series")
+	}
+	if config.XAxis.Numerator != nil || config.XAxis.Denominator != nil || config.XAxis.Operation != "" {
+		return fmt.Errorf("derived series (numerator, denominator, operation) apply only to y_axis")
+	}
+	if len(config.XAxis.SeriesFilter) > 0 {
+		return fmt.Errorf("series_filter applies only to y_axis series")
+	}
+	if config.XAxis.Aggregation != "" {
+		if err := validateAggregatedXAxis(config); err != nil {
+			return err
+		}
+	}
 
 	// Validate Y-axes
+	aliases := make(map[string]int, len(config.YAxis))
 	for i, yAxis := range config.YAxis {
-		if yAxis.Column == "" {
-			return fmt.Errorf("y_axis column is required at index %d", i)
+		alias := yAlias(i, yAxis)
+		if alias == "x_value" {
+			return fmt.Errorf("y_axis alias at index %d collides with the x_axis alias 'x_value'", i)
+		}
+		if alias == TotalCountColumn && config.IncludeTotalCount {
+			return fmt.Errorf("y_axis alias at index %d collides with the include_total_count column '%s'", i, TotalCountColumn)
+		}
+		if prev, ok := aliases[alias]; ok {
+			return fmt.Errorf("y_axis at indexes %d and %d share the alias '%s'; each series needs a unique alias", prev, i, alias)
+		}
+		aliases[alias] = i
+
+		derived := yAxis.Numerator != nil || yAxis.Denominator != nil || yAxis.Operation != ""
+		if derived {
+			if err := validateDerivedAxis(yAxis, i); err != nil {
+				return err
+			}
+		} else if yAxis.Column == "" && yAxis.Aggregation != "COUNT" {
+			return fmt.Errorf("y_axis column is required at index %d unless it counts rows with COUNT", i)
+		}
+		if len(yAxis.JSONPath) > 0 && !isColumnName(yAxis.Column) {
+			return fmt.Errorf("y_axis json_path at index %d requires a plain column name, got '%s'", i, yAxis.Column)
+		}
+		if len(yAxis.JSONPath) > 0 && dialect != DialectPostgres {
+			return fmt.Errorf("y_axis json_path at index %d is only supported by the postgres dialect", i)
+		}
+
+		if yAxis.Cast != "" && !isTypeName(yAxis.Cast) {
+			return fmt.Errorf("invalid cast '%s' for y_axis at index %d", yAxis.Cast, i)
+		}
+
+		if yAxis.AxisSide != "" && !contains(ValidAxisSides, yAxis.AxisSide) {
+			return fmt.Errorf("invalid axis_side '%s' for y_axis at index %d. Must be one of: %s",
+				yAxis.AxisSide, i, strings.Join(ValidAxisSides, ", "))
+		}
+
+		if yAxis.NullAs != nil && !nullAsMatchesDataType(yAxis.NullAs, yAxis.DataType) {
+			return fmt.Errorf("null_as %v for y_axis at index %d does not match data_type '%s'", yAxis.NullAs, i, yAxis.DataType)
 		}
 
 		if yAxis.Aggregation != "" {
-			validAggregations := []string{"SUM", "COUNT", "AVG", "MIN", "MAX"}
-			if !contains(validAggregations, yAxis.Aggregation) {
-				return fmt.Errorf("invalid aggregation '%s' for y_axis at index %d. Must be one of: %s",
-					yAxis.Aggregation, i, strings.Join(validAggregations, ", "))
+			if err := validateAggregation(config, yAxis, fmt.Sprintf("y_axis at index %d", i)); err != nil {
+				return err
+			}
+		}
+		if yAxis.Aggregation == AggregationPercent {
+			if yAxis.DataType != "" && yAxis.DataType != DataTypeNumeric {
+				return fmt.Errorf("%s aggregation for y_axis at index %d requires a numeric column, got data_type '%s'", AggregationPercent, i, yAxis.DataType)
+			}
+			if len(config.GroupBy) == 0 {
+				return fmt.Errorf("%s aggregation for y_axis at index %d requires group_by", AggregationPercent, i)
+			}
+		}
+		if len(yAxis.SeriesFilter) > 0 {
+			if err := validateSeriesFilter(config, yAxis, i); err != nil {
+				return err
 			}
 		}
 	}
 
-	// Validate filters
-	for i, filter := range config.Filters {
-		if err := validateFilter(&filter, i); err != nil {
-			return err
+	// Validate grouping mode
+	if err := validateGroupByMode(config); err != nil {
+		return err
+	}
+
+	// Validate order by
+	for i, order := range config.OrderBy {
+		if order.Column == "" && order.Aggregation != "COUNT" {
+			return fmt.Errorf("order_by column is required at index %d unless it counts rows with COUNT", i)
+		}
+		if order.Aggregation != "" {
+			if !contains(orderAggregations, order.Aggregation) {
+				return fmt.Errorf("invalid order_by aggregation '%s' at index %d. Must be one of: %s",
+					order.Aggregation, i, strings.Join(orderAggregations, ", "))
+			}
+			if len(config.GroupBy) == 0 {
+				return fmt.Errorf("order_by aggregation at index %d requires group_by", i)
+			}
+		}
+		if order.Direction != "" && !contains(ValidOrderDirections, strings.ToUpper(order.Direction)) {
+			return fmt.Errorf("invalid order_by direction '%s' at index %d. Must be one of: %s",
+				order.Direction, i, strings.Join(ValidOrderDirections, ", "))
 		}
 	}
 
-	return nil
-}
+	// Validate the total count. The window is computed before DISTINCT ON
+	// drops rows, so it would count them too.
+	if config.IncludeTotalCount && len(config.DistinctOn) > 0 {
+		return fmt.Errorf("include_total_count cannot be combined with distinct_on")
+	}
 
-// validateJoinConfig validates a join configuration
-func validateJoinConfig(join *JoinConfig, tableIndex, joinIndex int) error {
-	if join.Table == "" {
-		return fmt.Errorf("join table is required at table index %d, join index %d", tableIndex, joinIndex)
+	// Validate WITH TIES
+	if config.WithTies {
+		if dialect != DialectPostgres {
+			return fmt.Errorf("with_ties is only supported by the postgres dialect")
+		}
+		if len(config.OrderBy) == 0 {
+			return fmt.Errorf("with_ties requires order_by to decide which rows tie")
+		}
 	}
 
-	if join.Condition == "" {
-		return fmt.Errorf("join condition is required at table index %d, join index %d", tableIndex, joinIndex)
+	// Validate DISTINCT ON
+	if len(config.DistinctOn) > 0 {
+		if err := validateDistinctOn(config); err != nil {
+			return err
+		}
 	}
 
-	validJoinTypes := []string{"INNER", "LEFT", "RIGHT", "FULL"}
-	if join.Type != "" && !contains(validJoinTypes, join.Type) {
-		return fmt.Errorf("invalid join type '%s' at table index %d, join index %d. Must be one of: %s",
-			join.Type, tableIndex, joinIndex, strings.Join(validJoinTypes, ", "))
+	// Validate HAVING filters
+	for i, filter := range config.Having {
+		if err := validateHavingFilter(config, &filter, i); err != nil {
+			return err
+		}
 	}
 
-	return nil
-}
+	// Validate series colors
+	if err := validateColors(config); err != nil {
+		return err
+	}
 
-// validateFilter validates a filter configuration
-func validateFilter(filter *FilterConfig, index int) error {
-	if filter.Raw != "" {
-		return nil
+	// Validate scatter charts
+	if config.ChartType == ChartTypeScatter {
+		if err := validateScatter(config); err != nil {
+			return err
+		}
 	}
-	if filter.Column == "" {
-		return fmt.Errorf("filter column is required at index %d", index)
+
+	// Validate the bucket timezone
+	if config.Options.Timezone != "" {
+		if err := validateTimezone(config); err != nil {
+			return err
+		}
 	}
 
-	if filter.Operator == "" {
-		return fmt.Errorf("filter operator is required at index %d", index)
+	// Validate the fiscal year
+	if config.Options.FiscalYearStartMonth != 0 {
+		if err := validateFiscalYear(config); err != nil {
+			return err
+		}
 	}
 
-	validOperators := []string{"=", "!=", ">", "<", ">=", "<=", "IN", "LIKE", "BETWEEN", "IS"}
-	if !contains(validOperators, filter.Operator) {
-		return fmt.Errorf("invalid filter operator '%s' at index %d. Must be one of: %s",
-			filter.Operator, index, strings.Join(validOperators, ", "))
+	// Validate gap filling
+	if config.Options.GapFill != nil {
+		if err := validateGapFill(config); err != nil {
+			return err
+		}
 	}
 
-	// Validate operator-specific requirements
-	switch filter.Operator {
-	case "IN":
-		if len(filter.Values) == 0 {
-			return fmt.Errorf("IN operator requires 'values' array at filter index %d", index)
+	// Validate tenant filter
+	if config.Tenant != nil {
+		if !isColumnName(config.Tenant.Column) {
+			return fmt.Errorf("invalid tenant column '%s': must be a plain column name", config.Tenant.Column)
 		}
-	case "BETWEEN":
-		if len(filter.Values) != 2 {
-			return fmt.Errorf("BETWEEN operator requires exactly 2 values at filter index %d", index)
+		if config.Tenant.Value == nil {
+			return fmt.Errorf("tenant value is required")
 		}
-	case "IS":
-		// IS typically used with NULL, allow both value and values to be empty
-	default:
-		if filter.Value == nil && len(filter.Values) == 0 {
-			return fmt.Errorf("filter value is required for operator '%s' at index %d", filter.Operator, index)
+	}
+
+	// Validate the tables against the allowlist
+	if config.TableAllowlist != nil {
+		if err := validateTableAllowlist(config); err != nil {
+			return err
+		}
+	}
+
+	// Validate filters
+	for i, filter := range config.Filters {
+		if err := validateWhereFilter(config, &filter, i); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// contains checks if a slice contains a specific string
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
+// validateWhereFilter validates a filter that is rendered as a WHERE
+// predicate: an entry of filters or of a Y axis's series_filter
+func validateWhereFilter(config *ChartConfig, filter *FilterConfig, index int) error {
+	dialect := dialectOf(config)
+	if err := validateFilter(filter, index); err != nil {
+		return err
 	}
-	return false
+	if err := validateWhereAlias(config, filter, index); err != nil {
+		return err
+	}
+	if config.Tenant != nil && filter.Raw != "" && !isSelfContainedSQL(filter.Raw) {
+		return fmt.Errorf("raw filter at index %d must be a self-contained expression when a tenant filter is set", index)
+	}
+	if config.Tenant != nil && filter.Subquery != "" && !isSelfContainedSQL(filter.Subquery) {
+		return fmt.Errorf("subquery at filter index %d must be a self-contained expression when a tenant filter is set", index)
+	}
+	if dialect != DialectPostgres && contains([]string{"@>", "<@", "ANY"}, filter.Operator) {
+		return fmt.Errorf("filter operator '%s' at index %d is only supported by the postgres dialect", filter.Operator, index)
+	}
+	if dialect != DialectPostgres && len(filter.JSONPath) > 0 {
+		return fmt.Errorf("filter json_path at index %d is only supported by the postgres dialect", index)
+	}
+	return nil
 }
 
-// ParseChartConfigFromFile reads and unmarshals a chart configuration from a file
-func ParseChartConfigFromFile(filename string) (*ChartConfig, error) {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+// validateDistinctOn checks DISTINCT ON is supported and that ORDER BY leads
+// with the DISTINCT ON columns, which Postgres requires
+func validateDistinctOn(config *ChartConfig) error {
+	if dialectOf(config) != DialectPostgres {
+		return fmt.Errorf("distinct_on is only supported by the postgres dialect")
+	}
+	for i, column := range config.DistinctOn {
+		if column == "" {
+			return fmt.Errorf("distinct_on column is required at index %d", i)
+		}
 	}
 
-	return UnmarshalChartConfig(string(data))
+	if len(config.OrderBy) < len(config.DistinctOn) {
+		return fmt.Errorf("order_by must lead with the distinct_on columns %s to choose which row is kept",
+			strings.Join(config.DistinctOn, ", "))
+	}
+	for i := range config.DistinctOn {
+		if !contains(config.DistinctOn, config.OrderBy[i].Column) || config.OrderBy[i].Aggregation != "" {
+			return fmt.Errorf("order_by must lead with the distinct_on columns %s, but order_by[%d] is '%s'",
+				strings.Join(config.DistinctOn, ", "), i, config.OrderBy[i].Column)
+		}
+	}
+
+	return nil
 }
 
-// SaveChartConfigToFile marshals and saves a chart configuration to a file
-func SaveChartConfigToFile(config *ChartConfig, filename string) error {
-	jsonStr, err := MarshalChartConfig(config)
+// validateGroupByMode checks the grouping mode is known, has the columns it
+// groups by and is supported by the dialect
+func validateGroupByMode(config *ChartConfig) error {
+	if config.GroupByMode != "" && !contains(ValidGroupByModes, config.GroupByMode) {
+		return fmt.Errorf("invalid group_by_mode '%s'. Must be one of: %s",
+			config.GroupByMode, strings.Join(ValidGroupByModes, ", "))
+	}
+
+	switch config.GroupByMode {
+	case GroupByModeRollup, GroupByModeCube:
+		if len(config.GroupBy) == 0 {
+			return fmt.Errorf("group_by_mode '%s' requires group_by", config.GroupByMode)
+		}
+	case GroupByModeGroupingSets:
+		if len(config.GroupingSets) == 0 {
+			return fmt.Errorf("group_by_mode '%s' requires grouping_sets", config.GroupByMode)
+		}
+	}
+	if len(config.GroupingSets) > 0 && config.GroupByMode != GroupByModeGroupingSets {
+		return fmt.Errorf("grouping_sets requires group_by_mode '%s'", GroupByModeGroupingSets)
+	}
+
+	// groupByClause reports modes the dialect cannot express
+	_, err := groupByClause(config)
+	return err
+}
+
+// validateWhereAlias checks a filter naming x_value or a Y-axis alias, which
+// is replaced by the expression the axis selects. Aggregates cannot appear in
+// WHERE, so aliases of aggregated axes must be filtered with having.
+func validateWhereAlias(config *ChartConfig, filter *FilterConfig, index int) error {
+	if filter.Column == "x_value" {
+		if config.XAxis.Aggregation != "" {
+			return fmt.Errorf("filter at index %d references x_value, which is aggregated; filter it with having", index)
+		}
+		if len(filter.JSONPath) > 0 {
+			return fmt.Errorf("filter at index %d cannot apply json_path to x_value", index)
+		}
+		return nil
+	}
+
+	for i, yAxis := range config.YAxis {
+		if yAlias(i, yAxis) != filter.Column || yAxis.Column == filter.Column {
+			continue
+		}
+		if yAxis.aggregated() {
+			return fmt.Errorf("filter at index %d references y_axis alias '%s', which is aggregated; filter it with having", index, filter.Column)
+		}
+		if len(filter.JSONPath) > 0 {
+			return fmt.Errorf("filter at index %d cannot apply json_path to y_axis alias '%s'", index, filter.Column)
+		}
+	}
+	return nil
+}
+
+// validateHavingFilter validates a HAVING filter, whose column must be an
+// aggregate expression such as "SUM(amount)" or the alias of an aggregated
+// Y axis
+func validateHavingFilter(config *ChartConfig, filter *FilterConfig, index int) error {
+	if filter.Raw != "" {
+		if config.Tenant != nil && !isSelfContainedSQL(filter.Raw) {
+			return fmt.Errorf("raw having filter at index %d must be a self-contained expression when a tenant filter is set", index)
+		}
+		return nil
+	}
+	if len(filter.Columns) > 0 || len(filter.JSONPath) > 0 || isExistsOperator(filter.Operator) || filter.Relative != nil {
+		return fmt.Errorf("having filter at index %d must compare a single aggregate", index)
+	}
+
+	if err := validateFilter(filter, index); err != nil {
+		return fmt.Errorf("having: %w", err)
+	}
+
+	for i, yAxis := range config.YAxis {
+		if yAlias(i, yAxis) != filter.Column {
+			continue
+		}
+		if !yAxis.aggregated() {
+			return fmt.Errorf("having filter at index %d references y_axis alias '%s', which is not aggregated", index, filter.Column)
+		}
+		if yAxis.Aggregation == AggregationPercent {
+			return fmt.Errorf("having filter at index %d cannot reference the %s y_axis alias '%s'", index, AggregationPercent, filter.Column)
+		}
+		return nil
+	}
+
+	if !isAggregateExpr(filter.Column) {
+		return fmt.Errorf("having filter at index %d must reference an aggregate expression or an aggregated y_axis alias, got '%s'", index, filter.Column)
+	}
+
+	return nil
+}
+
+// isAggregateExpr reports whether expr is an aggregate call such as
+// "SUM(amount)" or "count(*)"
+func isAggregateExpr(expr string) bool {
+	name, _, ok := strings.Cut(expr, "(")
+	if !ok || !strings.HasSuffix(strings.TrimSpace(expr), ")") {
+		return false
+	}
+	return contains([]string{"SUM", "COUNT", "AVG", "MIN", "MAX", "STDDEV", "VARIANCE", "PERCENTILE_CONT", "STRING_AGG", "GROUP_CONCAT", "MODE"}, strings.ToUpper(strings.TrimSpace(name)))
+}
+
+// validateColors checks there is a color for every Y series: Colors is
+// either empty or long enough for each series, by position, that has no
+// SeriesColors entry
+func validateColors(config *ChartConfig) error {
+	aliases := make([]string, len(config.YAxis))
+	for i, yAxis := range config.YAxis {
+		aliases[i] = yAlias(i, yAxis)
+	}
+
+	for alias := range config.Options.SeriesColors {
+		if !contains(aliases, alias) {
+			return fmt.Errorf("series_colors references unknown y_axis alias '%s'", alias)
+		}
+	}
+
+	needed := 0
+	for i, alias := range aliases {
+		if _, ok := config.Options.SeriesColors[alias]; !ok {
+			needed = i + 1
+		}
+	}
+	if colors := config.Options.Colors; len(colors) > 0 && len(colors) < needed {
+		return fmt.Errorf("options.colors has %d colors for %d y_axis series; provide none or one per series", len(colors), needed)
+	}
+
+	return nil
+}
+
+// validateScatter checks a scatter chart selects raw numeric X/Y pairs, one
+// point per row, with no aggregation or grouping
+func validateScatter(config *ChartConfig) error {
+	if config.XAxis.Aggregation != "" {
+		return fmt.Errorf("scatter charts plot raw rows; x_axis cannot be aggregated")
+	}
+	if config.XAxis.DataType != "" && config.XAxis.DataType != DataTypeNumeric {
+		return fmt.Errorf("scatter charts require a numeric x_axis, got data_type '%s'", config.XAxis.DataType)
+	}
+
+	for i, yAxis := range config.YAxis {
+		if yAxis.aggregated() {
+			return fmt.Errorf("scatter charts plot raw rows; y_axis at index %d cannot be aggregated", i)
+		}
+		if yAxis.DataType != "" && yAxis.DataType != DataTypeNumeric {
+			return fmt.Errorf("scatter charts require a numeric y_axis, got data_type '%s' at index %d", yAxis.DataType, i)
+		}
+	}
+
+	if len(config.GroupBy) > 0 || len(config.Having) > 0 {
+		return fmt.Errorf("scatter charts plot raw rows and cannot use group_by or having")
+	}
+
+	return nil
+}
+
+// validateDerivedAxis checks a Y series computed from a numerator and a
+// denominator: both operands are set and numeric, and either both or
+// neither are aggregated
+func validateDerivedAxis(axis AxisConfig, index int) error {
+	if axis.Numerator == nil || axis.Denominator == nil {
+		return fmt.Errorf("derived y_axis at index %d requires both a numerator and a denominator", index)
+	}
+	if axis.Column != "" || axis.Aggregation != "" || len(axis.JSONPath) > 0 {
+		return fmt.Errorf("derived y_axis at index %d cannot also set column, aggregation or json_path", index)
+	}
+	if axis.Operation != "" && !contains(ValidOperations, axis.Operation) {
+		return fmt.Errorf("invalid operation '%s' for y_axis at index %d. Must be one of: %s",
+			axis.Operation, index, strings.Join(ValidOperations, ", "))
+	}
+	if axis.DataType != "" && axis.DataType != DataTypeNumeric {
+		return fmt.Errorf("derived y_axis at index %d must be numeric, got data_type '%s'", index, axis.DataType)
+	}
+
+	for _, operand := range []struct {
+		name string
+		*Operand
+	}{{"numerator", axis.Numerator}, {"denominator", axis.Denominator}} {
+		if operand.Column == "" && operand.Aggregation != "COUNT" {
+			return fmt.Errorf("%s column is required for y_axis at index %d", operand.name, index)
+		}
+		if operand.Aggregation != "" && !contains([]string{"SUM", "COUNT", "AVG", "MIN", "MAX"}, operand.Aggregation) {
+			return fmt.Errorf("invalid %s aggregation '%s' for y_axis at index %d. Must be one of: SUM, COUNT, AVG, MIN, MAX",
+				operand.name, operand.Aggregation, index)
+		}
+	}
+	if (axis.Numerator.Aggregation == "") != (axis.Denominator.Aggregation == "") {
+		return fmt.Errorf("numerator and denominator of y_axis at index %d must both be aggregated or both not", index)
+	}
+
+	return nil
+}
+
+// validateTableSample checks a TABLESAMPLE clause
+func validateTableSample(sample *TableSample, index int, dialect string) error {
+	if dialect != DialectPostgres {
+		return fmt.Errorf("sample at table index %d is only supported by the postgres dialect", index)
+	}
+	if index > 0 {
+		return fmt.Errorf("sample at table index %d applies only to the first table", index)
+	}
+	if !contains(ValidSampleMethods, strings.ToUpper(sample.Method)) {
+		return fmt.Errorf("invalid sample method '%s' at table index %d. Must be one of: %s",
+			sample.Method, index, strings.Join(ValidSampleMethods, ", "))
+	}
+	if sample.Percent <= 0 || sample.Percent > 100 {
+		return fmt.Errorf("sample percent at table index %d must be greater than 0 and at most 100, got %v", index, sample.Percent)
+	}
+	return nil
+}
+
+// validateTableAliases checks no two tables or joins share an alias. A table
+// without an alias is referenced by its name, without any schema prefix, so
+// joining a table to itself requires an alias.
+func validateTableAliases(config *ChartConfig) error {
+	seen := make(map[string]string)
+	add := func(name, alias, where string) error {
+		key := alias
+		if key == "" {
+			key = name[strings.LastIndex(name, ".")+1:]
+		}
+		key = strings.ToLower(key)
+		if prev, ok := seen[key]; ok {
+			return fmt.Errorf("%s and %s are both referenced as '%s'; give one of them a unique alias", prev, where, key)
+		}
+		seen[key] = where
+		return nil
+	}
+
+	for i, table := range config.Tables {
+		if err := add(table.Name, table.Alias, fmt.Sprintf("table at index %d", i)); err != nil {
+			return err
+		}
+		for j, join := range table.Joins {
+			if err := add(join.Table, join.Alias, fmt.Sprintf("join at table index %d, join index %d", i, j)); err != nil {
+				return err
+			}
+		}
+	}
+	for i, join := range config.Joins {
+		if err := add(join.Table, join.Alias, fmt.Sprintf("join at joins index %d", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateAggregation checks an axis aggregation is known, fits the axis's
+// data type and is supported by the dialect. name identifies the axis in
+// errors, e.g. "y_axis at index 2".
+func validateAggregation(config *ChartConfig, axis AxisConfig, name string) error {
+	if !contains(ValidAggregations, axis.Aggregation) {
+		return fmt.Errorf("invalid aggregation '%s' for %s. Must be one of: %s",
+			axis.Aggregation, name, strings.Join(ValidAggregations, ", "))
+	}
+
+	if axis.Aggregation == AggregationPercentile && (axis.Percentile <= 0 || axis.Percentile > 1) {
+		return fmt.Errorf("percentile %v for %s must be greater than 0 and at most 1, e.g. 0.95 for p95", axis.Percentile, name)
+	}
+	if axis.Percentile != 0 && axis.Aggregation != AggregationPercentile {
+		return fmt.Errorf("percentile for %s requires the %s aggregation, got '%s'", name, AggregationPercentile, axis.Aggregation)
+	}
+	if axis.Delimiter != "" && axis.Aggregation != AggregationStringAgg {
+		return fmt.Errorf("delimiter for %s requires the %s aggregation, got '%s'", name, AggregationStringAgg, axis.Aggregation)
+	}
+
+	switch axis.Aggregation {
+	case AggregationMedian, AggregationPercentile:
+		if dialect := dialectOf(config); dialect != DialectPostgres && dialect != DialectClickHouse {
+			return fmt.Errorf("%s aggregation for %s is only supported by the postgres and clickhouse dialects", axis.Aggregation, name)
+		}
+		if axis.DataType != "" && axis.DataType != DataTypeNumeric {
+			return fmt.Errorf("%s aggregation for %s requires a numeric column, got data_type '%s'", axis.Aggregation, name, axis.DataType)
+		}
+	case AggregationMode:
+		if dialectOf(config) != DialectPostgres {
+			return fmt.Errorf("%s aggregation for %s is only supported by the postgres dialect", axis.Aggregation, name)
+		}
+	case AggregationStringAgg:
+		if dialect := dialectOf(config); dialect == DialectMySQL || dialect == DialectClickHouse {
+			return fmt.Errorf("%s aggregation for %s is not supported by the %s dialect", axis.Aggregation, name, dialect)
+		}
+		if axis.DataType != "" && axis.DataType != DataTypeString {
+			return fmt.Errorf("%s aggregation for %s requires a string column, got data_type '%s'", axis.Aggregation, name, axis.DataType)
+		}
+	case "STDDEV", "VARIANCE":
+		if dialectOf(config) == DialectSQLite {
+			return fmt.Errorf("%s aggregation for %s is not supported by the sqlite dialect", axis.Aggregation, name)
+		}
+		if axis.DataType != "" && axis.DataType != DataTypeNumeric {
+			return fmt.Errorf("%s aggregation for %s requires a numeric column, got data_type '%s'", axis.Aggregation, name, axis.DataType)
+		}
+	}
+
+	return nil
+}
+
+// validateAggregatedXAxis checks a chart whose X axis is an aggregate, e.g.
+// AVG(age) per customer against SUM(amount) per customer. The rows are the
+// groups of an explicit GroupBy, which cannot include the aggregated column,
+// and every unaggregated Y column must be grouped.
+func validateAggregatedXAxis(config *ChartConfig) error {
+	if err := validateAggregation(config, config.XAxis, "x_axis"); err != nil {
+		return err
+	}
+
+	if len(config.GroupBy) == 0 {
+		return fmt.Errorf("aggregated x_axis requires group_by to name the dimension each point aggregates over")
+	}
+
+	for _, column := range config.GroupBy {
+		if column == config.XAxis.Column || column == "x_value" {
+			return fmt.Errorf("group_by cannot include the aggregated x_axis column '%s'", column)
+		}
+	}
+
+	for i, yAxis := range config.YAxis {
+		if !yAxis.aggregated() && !contains(config.GroupBy, yAxis.Column) {
+			return fmt.Errorf("y_axis column '%s' at index %d must be aggregated or listed in group_by when x_axis is aggregated", yAxis.Column, i)
+		}
+	}
+
+	return nil
+}
+
+// nullAsMatchesDataType reports whether a NullAs default fits the axis data
+// type. An unknown data type accepts any default.
+func nullAsMatchesDataType(value interface{}, dataType string) bool {
+	switch dataType {
+	case DataTypeNumeric:
+		switch value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+			return true
+		}
+		return false
+	case DataTypeString:
+		_, ok := value.(string)
+		return ok
+	case DataTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case DataTypeDatetime:
+		switch value.(type) {
+		case time.Time, string:
+			return true
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// validateJoinConfig validates a join configuration. where locates it in
+// errors, e.g. "table index 0, join index 1".
+func validateJoinConfig(join *JoinConfig, where string) error {
+	if join.Table == "" {
+		return fmt.Errorf("join table is required at %s", where)
+	}
+
+	if join.Condition == "" {
+		return fmt.Errorf("join condition is required at %s", where)
+	}
+
+	if join.Type != "" && !contains(ValidJoinTypes, join.Type) {
+		return fmt.Errorf("invalid join type '%s' at %s. Must be one of: %s",
+			join.Type, where, strings.Join(ValidJoinTypes, ", "))
+	}
+
+	return nil
+}
+
+// validateFilter validates a filter configuration
+func validateFilter(filter *FilterConfig, index int) error {
+	if filter.Raw != "" {
+		return nil
+	}
+	if len(filter.Columns) > 0 {
+		return validateTupleFilter(filter, index)
+	}
+	if isExistsOperator(filter.Operator) {
+		return validateExistsFilter(filter, index)
+	}
+	if filter.Column == "" {
+		return fmt.Errorf("filter column is required at index %d", index)
+	}
+
+	if len(filter.JSONPath) > 0 && !isColumnName(filter.Column) {
+		return fmt.Errorf("filter json_path at index %d requires a plain column name, got '%s'", index, filter.Column)
+	}
+
+	if filter.Relative != nil {
+		return validateRelativeFilter(filter, index)
+	}
+	if isSubqueryOperator(filter.Operator) {
+		return validateSubqueryFilter(filter, index)
+	}
+	if filter.Subquery != "" {
+		return fmt.Errorf("subquery at filter index %d requires an ANY or ALL operator such as '= ANY'", index)
+	}
+
+	if filter.Operator == "" {
+		return fmt.Errorf("filter operator is required at index %d", index)
+	}
+
+	if !contains(ValidOperators, filter.Operator) {
+		return fmt.Errorf("invalid filter operator '%s' at index %d. Must be one of: %s",
+			filter.Operator, index, strings.Join(ValidOperators, ", "))
+	}
+
+	if filter.ValueExpr != "" {
+		return validateValueExpr(filter, index)
+	}
+
+	// Validate operator-specific requirements
+	switch filter.Operator {
+	case "IN", "NOT IN":
+		if len(filter.Values) == 0 {
+			return fmt.Errorf("%s operator requires 'values' array at filter index %d", filter.Operator, index)
+		}
+	case "BETWEEN":
+		if len(filter.Values) != 2 {
+			return fmt.Errorf("BETWEEN operator requires exactly 2 values at filter index %d", index)
+		}
+	case "@>", "<@":
+		// Postgres array containment: the values form the array operand
+		if len(filter.Values) == 0 {
+			return fmt.Errorf("%s operator requires 'values' array at filter index %d", filter.Operator, index)
+		}
+	case "ANY":
+		// Postgres array membership: value = ANY(column)
+		if filter.Value == nil {
+			return fmt.Errorf("ANY operator requires a non-null 'value' at filter index %d", index)
+		}
+	case "IS", "IS NOT":
+		// IS [NOT] NULL, IS [NOT] TRUE or IS [NOT] FALSE: a NULL or boolean value
+		if len(filter.Values) > 0 {
+			return fmt.Errorf("%s operator takes a single 'value', not 'values', at filter index %d", filter.Operator, index)
+		}
+		if !isOperand(filter.Value) {
+			return fmt.Errorf("%s operator requires a NULL or boolean value at filter index %d, got %v", filter.Operator, index, filter.Value)
+		}
+	case "IS NULL", "IS NOT NULL":
+		// The operand is part of the operator
+		if filter.Value != nil || len(filter.Values) > 0 {
+			return fmt.Errorf("%s operator takes no value at filter index %d", filter.Operator, index)
+		}
+	default:
+		if filter.Value == nil && len(filter.Values) == 0 {
+			return fmt.Errorf("filter value is required for operator '%s' at index %d", filter.Operator, index)
+		}
+	}
+
+	return nil
+}
+
+// validateValueExpr validates a filter comparing its column with a SQL
+// expression instead of a bound value
+func validateValueExpr(filter *FilterConfig, index int) error {
+	if filter.Value != nil || len(filter.Values) > 0 {
+		return fmt.Errorf("filter at index %d cannot set both value_expr and a value", index)
+	}
+	if !contains(comparisonOperators, filter.Operator) {
+		return fmt.Errorf("value_expr at filter index %d requires one of the operators: %s",
+			index, strings.Join(comparisonOperators, ", "))
+	}
+	if !isSelfContainedSQL(filter.ValueExpr) {
+		return fmt.Errorf("value_expr at filter index %d must be a self-contained expression", index)
+	}
+	if strings.ContainsAny(filter.ValueExpr, "?$") {
+		return fmt.Errorf("value_expr at filter index %d cannot contain placeholders; use value to bind parameters", index)
+	}
+	return nil
+}
+
+// isOperand reports whether value can follow IS: NULL, a boolean, or "true"
+// or "false"
+func isOperand(value interface{}) bool {
+	switch v := value.(type) {
+	case nil, bool:
+		return true
+	case string:
+		return strings.EqualFold(v, "true") || strings.EqualFold(v, "false")
+	}
+	return false
+}
+
+// orderAggregations are the aggregations an order_by clause can apply
+var orderAggregations = []string{"SUM", "COUNT", "AVG", "MIN", "MAX"}
+
+// comparisonOperators are the operators a value_expr can be compared with
+var comparisonOperators = []string{"=", "!=", ">", "<", ">=", "<="}
+
+// validateSubqueryFilter validates an "op ANY"/"op ALL" subquery filter
+func validateSubqueryFilter(filter *FilterConfig, index int) error {
+	if filter.Subquery == "" {
+		return fmt.Errorf("%s operator requires a 'subquery' at filter index %d", filter.Operator, index)
+	}
+
+	if filter.Value != nil || len(filter.Values) > 0 {
+		return fmt.Errorf("%s operator takes a subquery, not a value, at filter index %d", filter.Operator, index)
+	}
+
+	return nil
+}
+
+// validateExistsFilter validates an EXISTS/NOT EXISTS filter, which takes a
+// subquery and no column or value
+func validateExistsFilter(filter *FilterConfig, index int) error {
+	if filter.Subquery == "" {
+		return fmt.Errorf("%s operator requires a 'subquery' at filter index %d", filter.Operator, index)
+	}
+
+	if filter.Column != "" || len(filter.JSONPath) > 0 {
+		return fmt.Errorf("%s filter at index %d cannot set a column", filter.Operator, index)
+	}
+
+	if filter.Value != nil || len(filter.Values) > 0 {
+		return fmt.Errorf("%s operator takes a subquery, not a value, at filter index %d", filter.Operator, index)
+	}
+
+	return nil
+}
+
+// isExistsOperator reports whether op is EXISTS or NOT EXISTS
+func isExistsOperator(op string) bool {
+	op = strings.Join(strings.Fields(strings.ToUpper(op)), " ")
+	return op == "EXISTS" || op == "NOT EXISTS"
+}
+
+// isSubqueryOperator reports whether op compares against a subquery with
+// ANY or ALL, e.g. "= ANY" or "> ALL"
+func isSubqueryOperator(op string) bool {
+	fields := strings.Fields(strings.ToUpper(op))
+	if len(fields) != 2 || (fields[1] != "ANY" && fields[1] != "ALL") {
+		return false
+	}
+	return contains(subqueryComparisons, fields[0])
+}
+
+// subqueryComparisons are the comparisons an ANY or ALL subquery filter takes
+var subqueryComparisons = []string{"=", "!=", "<>", ">", "<", ">=", "<="}
+
+// validateTupleFilter validates a multi-column IN filter
+func validateTupleFilter(filter *FilterConfig, index int) error {
+	if filter.Column != "" {
+		return fmt.Errorf("filter at index %d cannot set both column and columns", index)
+	}
+
+	if len(filter.JSONPath) > 0 {
+		return fmt.Errorf("json_path is not supported on multi-column filter at index %d", index)
+	}
+
+	if filter.Operator != "IN" && filter.Operator != "NOT IN" {
+		return fmt.Errorf("multi-column filter at index %d requires operator IN or NOT IN, got '%s'", index, filter.Operator)
+	}
+
+	for i, column := range filter.Columns {
+		if column == "" {
+			return fmt.Errorf("filter column is required at index %d, column index %d", index, i)
+		}
+	}
+
+	if len(filter.Tuples) == 0 {
+		return fmt.Errorf("multi-column IN requires 'tuples' array at filter index %d", index)
+	}
+
+	for i, tuple := range filter.Tuples {
+		if len(tuple) != len(filter.Columns) {
+			return fmt.Errorf("tuple at index %d has %d values but filter index %d has %d columns",
+				i, len(tuple), index, len(filter.Columns))
+		}
+	}
+
+	return nil
+}
+
+// isColumnName reports whether s is a plain, optionally table-qualified,
+// column name such as "data" or "e.data" rather than an expression
+func isColumnName(s string) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) > 2 {
+		return false
+	}
+	for _, part := range parts {
+		if !isIdentifier(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifier reports whether s is a bare SQL identifier
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '$'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isTypeName reports whether s is a SQL type name such as "double precision",
+// "numeric(18, 2)" or "varchar(64)": identifiers separated by spaces with an
+// optional numeric modifier
+func isTypeName(s string) bool {
+	name, modifier, hasModifier := strings.Cut(s, "(")
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words {
+		if !isIdentifier(word) {
+			return false
+		}
+	}
+	if !hasModifier {
+		return true
+	}
+
+	modifier, ok := strings.CutSuffix(strings.TrimSpace(modifier), ")")
+	if !ok {
+		return false
+	}
+	for _, part := range strings.Split(modifier, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// contains checks if a slice contains a specific string
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalChartConfigYAML unmarshals YAML into a ChartConfig struct. It
+// validates exactly like UnmarshalChartConfig.
+func UnmarshalChartConfigYAML(data []byte) (*ChartConfig, error) {
+	var config ChartConfig
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+
+	if err := validateChartConfig(&config); err != nil {
+		return nil, fmt.Errorf("invalid chart configuration: %w", err)
+	}
+
+	return &config, nil
+}
+
+// MarshalChartConfigYAML marshals a ChartConfig struct to YAML
+func MarshalChartConfigYAML(config *ChartConfig) ([]byte, error) {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ChartConfig: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalChartConfigTOML unmarshals TOML into a ChartConfig struct. It
+// validates exactly like UnmarshalChartConfig.
+//
+// TOML has no null, so a filter matches NULL through the "IS NULL" or
+// "IS NOT NULL" operator, or "IS" / "IS NOT" with the value left out, and IN
+// lists cannot contain NULL. Integers decode as int64 rather than JSON's
+// float64, and TOML datetimes decode as time.Time.
+func UnmarshalChartConfigTOML(data []byte) (*ChartConfig, error) {
+	var config ChartConfig
+
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal TOML: %w", err)
+	}
+
+	if err := validateChartConfig(&config); err != nil {
+		return nil, fmt.Errorf("invalid chart configuration: %w", err)
+	}
+
+	return &config, nil
+}
+
+// MarshalChartConfigTOML marshals a ChartConfig struct to TOML. A nil filter
+// value is left out and decodes back as nil, but it fails if a list such as
+// values holds a nil, since TOML cannot represent null.
+func MarshalChartConfigTOML(config *ChartConfig) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+		return nil, fmt.Errorf("failed to marshal ChartConfig: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// isYAMLFile reports whether a filename has a YAML extension
+func isYAMLFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// isTOMLFile reports whether a filename has a TOML extension
+func isTOMLFile(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".toml"
+}
+
+// ParseChartConfigFromFile reads and unmarshals a chart configuration from a
+// file. Files ending in .yaml or .yml are parsed as YAML, .toml as TOML and
+// others as JSON.
+func ParseChartConfigFromFile(filename string) (*ChartConfig, error) {
+	data, err := os.ReadFile(filename)
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	if isYAMLFile(filename) {
+		return UnmarshalChartConfigYAML(data)
+	}
+	if isTOMLFile(filename) {
+		return UnmarshalChartConfigTOML(data)
+	}
+
+	return UnmarshalChartConfig(string(data))
+}
+
+// SaveChartConfigToFile marshals and saves a chart configuration to a file,
+// as YAML for .yaml/.yml files, TOML for .toml files and JSON otherwise
+func SaveChartConfigToFile(config *ChartConfig, filename string) error {
+	var data []byte
+	if isYAMLFile(filename) {
+		yamlData, err := MarshalChartConfigYAML(config)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		data = yamlData
+	} else if isTOMLFile(filename) {
+		tomlData, err := MarshalChartConfigTOML(config)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		data = tomlData
+	} else {
+		jsonStr, err := MarshalChartConfig(config)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		data = []byte(jsonStr)
 	}
 
-	if err := os.WriteFile(filename, []byte(jsonStr), 0644); err != nil {
+	if err := os.WriteFile(filename, data, 0644); err != nil {
 		return fmt.Errorf("failed to write file %s: %w", filename, err)
 	}
 
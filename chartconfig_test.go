@@ -0,0 +1,347 @@
+package chatabase
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testConfig returns a valid bar chart of SUM(o.amount) by o.region over
+// orders aliased o, with the given filters
+func testConfig(filters ...FilterConfig) *ChartConfig {
+	return &ChartConfig{
+		ChartType: "bar",
+		Title:     "Orders",
+		Tables:    []TableConfig{{Name: "orders", Alias: "o"}},
+		XAxis:     AxisConfig{Column: "o.region", Label: "Region"},
+		YAxis:     []AxisConfig{{Column: "o.amount", Label: "Amount", Aggregation: "SUM"}},
+		GroupBy:   []string{"o.region"},
+		Filters:   filters,
+	}
+}
+
+func TestValidateNotIn(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter FilterConfig
+		want   string
+	}{
+		{
+			name:   "single column",
+			filter: FilterConfig{Column: "o.status", Operator: "NOT IN", Values: []interface{}{"paid", "refunded"}},
+			want:   "(o.status NOT IN ($1, $2) OR o.status IS NULL)",
+		},
+		{
+			name:   "tuple",
+			filter: FilterConfig{Columns: []string{"o.country", "o.plan"}, Operator: "NOT IN", Tuples: [][]interface{}{{"US", "pro"}}},
+			want:   "(o.country, o.plan) NOT IN (($1, $2))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(tt.filter)
+			if err := validateChartConfig(config); err != nil {
+				t.Fatalf("validateChartConfig() error = %v", err)
+			}
+			query, _, err := BuildChartQuery(config)
+			if err != nil {
+				t.Fatalf("BuildChartQuery() error = %v", err)
+			}
+			if !strings.Contains(query, tt.want) {
+				t.Errorf("query %q does not contain %q", query, tt.want)
+			}
+		})
+	}
+
+	config := testConfig(FilterConfig{Column: "o.status", Operator: "NOT IN"})
+	if err := validateChartConfig(config); err == nil {
+		t.Error("validateChartConfig() accepted NOT IN without values")
+	}
+}
+
+// fullConfig returns a config with every slice, map and pointer Clone copies set
+func fullConfig() *ChartConfig {
+	filter := func() FilterConfig {
+		return FilterConfig{
+			Column:       "o.status",
+			Operator:     "IN",
+			Values:       []interface{}{"paid", "refunded"},
+			Columns:      []string{"o.country", "o.plan"},
+			Tuples:       [][]interface{}{{"US", "pro"}},
+			JSONPath:     []string{"source"},
+			SubqueryArgs: []interface{}{1},
+			Relative:     &RelativeDate{Count: 7, Unit: "day"},
+			RawValues:    []interface{}{2},
+		}
+	}
+	return &ChartConfig{
+		ChartType: "bar",
+		Title:     "Orders",
+		Tables: []TableConfig{{
+			Name:   "orders",
+			Alias:  "o",
+			Joins:  []JoinConfig{{Table: "users", Alias: "u", Type: "LEFT", Condition: "u.id = o.user_id"}},
+			Sample: &TableSample{Method: SampleSystem, Percent: 10},
+		}},
+		Joins: []JoinConfig{{Table: "plans", Alias: "p", Condition: "p.id = u.plan_id"}},
+		XAxis: AxisConfig{Column: "o.region", JSONPath: []string{"region"}},
+		YAxis: []AxisConfig{{
+			Column:       "o.amount",
+			Aggregation:  "SUM",
+			JSONPath:     []string{"amount"},
+			Numerator:    &Operand{Column: "o.amount", Aggregation: "SUM"},
+			Denominator:  &Operand{Column: "o.id", Aggregation: "COUNT"},
+			SeriesFilter: []FilterConfig{filter()},
+		}},
+		GroupBy:      []string{"o.region"},
+		GroupingSets: [][]string{{"o.region"}, {}},
+		Filters:      []FilterConfig{filter()},
+		Having:       []FilterConfig{filter()},
+		Options: ChartOptions{
+			Colors:       []string{"#111111"},
+			SeriesColors: map[string]string{"y_value_1": "#222222"},
+			GapFill:      &GapFill{Start: "2024-01-01", Step: "1 day"},
+		},
+		OrderBy:        []OrderConfig{{Column: "x_value", Direction: "ASC"}},
+		DistinctOn:     []string{"o.region"},
+		TableAllowlist: []string{"orders"},
+		Tenant:         &TenantFilter{Column: "tenant_id", Value: 1},
+	}
+}
+
+func TestCloneIsDeep(t *testing.T) {
+	original := fullConfig()
+	clone := original.Clone()
+	if !reflect.DeepEqual(clone, original) {
+		t.Fatalf("Clone() = %+v, want %+v", clone, original)
+	}
+
+	mutateFilter := func(f *FilterConfig) {
+		f.Values[0] = "changed"
+		f.Columns[0] = "changed"
+		f.Tuples[0][0] = "changed"
+		f.JSONPath[0] = "changed"
+		f.SubqueryArgs[0] = "changed"
+		f.Relative.Count = 99
+		f.RawValues[0] = "changed"
+	}
+	clone.Tables[0].Joins[0].Table = "changed"
+	clone.Tables[0].Sample.Percent = 99
+	clone.Joins[0].Table = "changed"
+	clone.XAxis.JSONPath[0] = "changed"
+	clone.YAxis[0].JSONPath[0] = "changed"
+	clone.YAxis[0].Numerator.Column = "changed"
+	clone.YAxis[0].Denominator.Column = "changed"
+	mutateFilter(&clone.YAxis[0].SeriesFilter[0])
+	clone.GroupBy[0] = "changed"
+	clone.GroupingSets[0][0] = "changed"
+	mutateFilter(&clone.Filters[0])
+	mutateFilter(&clone.Having[0])
+	clone.Options.Colors[0] = "changed"
+	clone.Options.SeriesColors["y_value_1"] = "changed"
+	clone.Options.GapFill.Step = "changed"
+	clone.OrderBy[0].Column = "changed"
+	clone.DistinctOn[0] = "changed"
+	clone.TableAllowlist[0] = "changed"
+	clone.Tenant.Value = 2
+
+	if !reflect.DeepEqual(original, fullConfig()) {
+		t.Errorf("mutating the clone changed the original: %+v", original)
+	}
+}
+
+func TestRawFilterJSONRoundTrip(t *testing.T) {
+	original := testConfig(FilterConfig{
+		Raw:       "o.amount > ? AND o.status <> ?",
+		RawValues: []interface{}{float64(10), "void"},
+	})
+
+	data, err := MarshalChartConfig(original)
+	if err != nil {
+		t.Fatalf("MarshalChartConfig() error = %v", err)
+	}
+	if !strings.Contains(data, `"raw":`) || !strings.Contains(data, `"raw_values"`) {
+		t.Errorf("marshaled config lacks the raw and raw_values keys:\n%s", data)
+	}
+
+	decoded, err := UnmarshalChartConfig(data)
+	if err != nil {
+		t.Fatalf("UnmarshalChartConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Filters, original.Filters) {
+		t.Errorf("round-tripped filters = %+v, want %+v", decoded.Filters, original.Filters)
+	}
+}
+
+func TestTOMLNullFilters(t *testing.T) {
+	data := []byte(`
+chart_type = "bar"
+title = "Orders"
+group_by = ["o.region"]
+
+[[tables]]
+  name = "orders"
+  alias = "o"
+
+[x_axis]
+  column = "o.region"
+
+[[y_axis]]
+  column = "o.amount"
+  aggregation = "SUM"
+
+[[filters]]
+  column = "o.status"
+  operator = "IS NULL"
+
+[[filters]]
+  column = "o.region"
+  operator = "IS NOT NULL"
+
+[[filters]]
+  column = "o.paid"
+  operator = "IS NOT"
+`)
+
+	config, err := UnmarshalChartConfigTOML(data)
+	if err != nil {
+		t.Fatalf("UnmarshalChartConfigTOML() error = %v", err)
+	}
+	query, _, err := BuildChartQuery(config)
+	if err != nil {
+		t.Fatalf("BuildChartQuery() error = %v", err)
+	}
+	want := "o.status IS NULL AND o.region IS NOT NULL AND o.paid IS NOT NULL"
+	if !strings.Contains(query, want) {
+		t.Errorf("query %q does not contain %q", query, want)
+	}
+
+	encoded, err := MarshalChartConfigTOML(config)
+	if err != nil {
+		t.Fatalf("MarshalChartConfigTOML() error = %v", err)
+	}
+	decoded, err := UnmarshalChartConfigTOML(encoded)
+	if err != nil {
+		t.Fatalf("UnmarshalChartConfigTOML() of the marshaled config error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Filters, config.Filters) {
+		t.Errorf("round-tripped filters = %+v, want %+v", decoded.Filters, config.Filters)
+	}
+}
+
+func TestUnmarshalSingleObjectOrArray(t *testing.T) {
+	tests := []struct {
+		name      string
+		unmarshal func([]byte) (*ChartConfig, error)
+		single    string
+		array     string
+	}{
+		{
+			name:      "JSON",
+			unmarshal: func(data []byte) (*ChartConfig, error) { return UnmarshalChartConfig(string(data)) },
+			single: `{"chart_type": "bar", "title": "Orders",
+				"tables": {"name": "orders", "alias": "o"},
+				"x_axis": {"column": "o.region"},
+				"y_axis": {"column": "o.amount", "aggregation": "SUM"},
+				"group_by": "o.region"}`,
+			array: `{"chart_type": "bar", "title": "Orders",
+				"tables": [{"name": "orders", "alias": "o"}],
+				"x_axis": {"column": "o.region"},
+				"y_axis": [{"column": "o.amount", "aggregation": "SUM"}],
+				"group_by": ["o.region"]}`,
+		},
+		{
+			name:      "YAML",
+			unmarshal: UnmarshalChartConfigYAML,
+			single: `
+chart_type: bar
+title: Orders
+tables: {name: orders, alias: o}
+x_axis: {column: o.region}
+y_axis: {column: o.amount, aggregation: SUM}
+group_by: o.region
+`,
+			array: `
+chart_type: bar
+title: Orders
+tables: [{name: orders, alias: o}]
+x_axis: {column: o.region}
+y_axis: [{column: o.amount, aggregation: SUM}]
+group_by: [o.region]
+`,
+		},
+		{
+			name:      "TOML",
+			unmarshal: UnmarshalChartConfigTOML,
+			single: `
+chart_type = "bar"
+title = "Orders"
+group_by = "o.region"
+tables = {name = "orders", alias = "o"}
+x_axis = {column = "o.region"}
+
+[y_axis]
+  column = "o.amount"
+  aggregation = "SUM"
+`,
+			array: `
+chart_type = "bar"
+title = "Orders"
+group_by = ["o.region"]
+x_axis = {column = "o.region"}
+
+[[tables]]
+  name = "orders"
+  alias = "o"
+
+[[y_axis]]
+  column = "o.amount"
+  aggregation = "SUM"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			single, err := tt.unmarshal([]byte(tt.single))
+			if err != nil {
+				t.Fatalf("single object form: %v", err)
+			}
+			array, err := tt.unmarshal([]byte(tt.array))
+			if err != nil {
+				t.Fatalf("array form: %v", err)
+			}
+			if !reflect.DeepEqual(single, array) {
+				t.Errorf("single object form = %+v, array form = %+v", single, array)
+			}
+			if len(single.Tables) != 1 || len(single.YAxis) != 1 || len(single.GroupBy) != 1 {
+				t.Errorf("got %d tables, %d y_axis and %d group_by entries, want 1 each",
+					len(single.Tables), len(single.YAxis), len(single.GroupBy))
+			}
+		})
+	}
+}
+
+func TestTimezoneRequiresGapFill(t *testing.T) {
+	config := testConfig(FilterConfig{Column: "o.created_at", Operator: "BETWEEN", Values: []interface{}{"2024-01-01", "2024-01-31"}})
+	config.XAxis = AxisConfig{Column: "o.created_at", DataType: DataTypeDatetime}
+	config.GroupBy = []string{"o.created_at"}
+	config.Options.TimeInterval = "day"
+	config.Options.Timezone = "America/New_York"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "requires options.gap_fill") {
+		t.Errorf("Validate() error = %v, want timezone to require options.gap_fill", err)
+	}
+
+	config.Options.GapFill = &GapFill{}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	query, _, err := BuildChartQuery(config)
+	if err != nil {
+		t.Fatalf("BuildChartQuery() error = %v", err)
+	}
+	if want := "DATE_TRUNC('day', o.created_at AT TIME ZONE 'America/New_York')"; !strings.Contains(query, want) {
+		t.Errorf("query %q does not contain %q", query, want)
+	}
+}
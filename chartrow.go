@@ -1,48 +1,268 @@
 package chatabase
 
 import (
-	"database/sql/driver"
-	"time"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
 )
 
 type ChartDataRow struct {
 	XValue  interface{} `json:"x_value"`
 	YValues interface{} `json:"y_values"` // this removes being boxed to an array of values, we can use maps
+
+	// Series lists the YValues keys in select order, for index-based access
+	Series []string `json:"-"`
+
+	// TotalCount is the number of rows the query matched before its limit,
+	// when the rows are scanned with a config setting IncludeTotalCount; it is
+	// 0 otherwise
+	TotalCount int64 `json:"total_count,omitempty"`
 }
 
-// GetYValueAsFloat gets a Y-value as float64 with null safety
-//func (row *ChartDataRow) GetYValueAsFloat(index int) *float64 {
-//	if index >= len(row.YValues) || row.YValues[index] == nil {
-//		return nil
-//	}
-//
-//	switch v := row.YValues[index].(type) {
-//	case float64:
-//		return &v
-//	case int64:
-//		f := float64(v)
-//		return &f
-//	case int:
-//		f := float64(v)
-//		return &f
-//	default:
-//		return nil
-//	}
-//}
+// GetYValueAsFloat gets a Y-value as float64 with null safety: it returns nil
+// for NULL, never a pointer to 0. Booleans read as 1 and 0. Numeric strings,
+// as drivers return Postgres numeric columns, are parsed; use AxisConfig.Cast
+// to have the database return double precision instead.
+func (row *ChartDataRow) GetYValueAsFloat(index int) *float64 {
+	if index < 0 || index >= len(row.Series) {
+		return nil
+	}
+	values, ok := row.YValues.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	switch v := values[row.Series[index]].(type) {
+	case float64:
+		return &v
+	case float32:
+		f := float64(v)
+		return &f
+	case int64:
+		f := float64(v)
+		return &f
+	case int32:
+		f := float64(v)
+		return &f
+	case int:
+		f := float64(v)
+		return &f
+	case bool:
+		f := 0.0
+		if v {
+			f = 1
+		}
+		return &f
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil
+		}
+		return &f
+	default:
+		return nil
+	}
+}
+
+// GetYValueAsDecimal gets a Y-value as an exact decimal with null safety. It
+// is exact for values scanned with ScanOptions.ExactDecimals or returned as
+// numeric strings; floats are converted as-is.
+func (row *ChartDataRow) GetYValueAsDecimal(index int) *decimal.Decimal {
+	if index < 0 || index >= len(row.Series) {
+		return nil
+	}
+	values, ok := row.YValues.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	switch v := values[row.Series[index]].(type) {
+	case decimal.Decimal:
+		return &v
+	case string:
+		d, err := decimal.NewFromString(v)
+		if err != nil {
+			return nil
+		}
+		return &d
+	case float64:
+		d := decimal.NewFromFloat(v)
+		return &d
+	case int64:
+		d := decimal.NewFromInt(v)
+		return &d
+	case int:
+		d := decimal.NewFromInt(int64(v))
+		return &d
+	default:
+		return nil
+	}
+}
+
+// SeriesBySide partitions the Y series result columns (aliases, as keyed in
+// ChartDataRow.YValues) by the axis they are drawn against. Series without an
+// AxisSide are on the left.
+func SeriesBySide(config *ChartConfig) (left, right []string) {
+	for i, yAxis := range config.YAxis {
+		if yAxis.AxisSide == AxisSideRight {
+			right = append(right, yAlias(i, yAxis))
+		} else {
+			left = append(left, yAlias(i, yAxis))
+		}
+	}
+	return left, right
+}
+
+// ColorForSeries returns the color of the Y series at index: its alias in
+// Options.SeriesColors, else Options.Colors by position, cycling when there
+// are fewer colors than series. It returns "" when no colors are set.
+func ColorForSeries(config *ChartConfig, index int) string {
+	if index >= 0 && index < len(config.YAxis) {
+		if color, ok := config.Options.SeriesColors[yAlias(index, config.YAxis[index])]; ok {
+			return color
+		}
+	}
+
+	colors := config.Options.Colors
+	if len(colors) == 0 || index < 0 {
+		return ""
+	}
+	return colors[index%len(colors)]
+}
 
 // ScanDynamicChart scans chart data with an unknown number of Y-values
 func ScanDynamicChart(rows *sqlx.Rows) ([]ChartDataRow, error) {
+	return ScanDynamicChartN(rows, 0)
+}
+
+// ScanDynamicChartN is ScanDynamicChart that stops after max rows, e.g. for a
+// preview of a large result. The remaining rows are left unread and the
+// caller must still Close rows. max <= 0 scans every row.
+func ScanDynamicChartN(rows *sqlx.Rows, max int) ([]ChartDataRow, error) {
+	return ScanDynamicChartOptions(rows, ScanOptions{MaxRows: max})
+}
+
+// ScanOptions controls how chart rows are scanned
+type ScanOptions struct {
+	// MaxRows stops scanning after this many rows; <= 0 scans every row
+	MaxRows int
+
+	// ExactDecimals scans NUMERIC and DECIMAL columns as decimal.Decimal
+	// instead of strings, so money totals keep every cent
+	ExactDecimals bool
+
+	// Config, when set, maps result columns to series by name instead of
+	// position: x_value is the X value and each Y axis is read from the
+	// column named by its alias. Other columns are ignored.
+	Config *ChartConfig
+}
+
+// ScanChartWithConfig scans chart rows, matching columns to config's series
+// by name rather than assuming X comes first and the Y series follow in
+// order. It fails when a series column is missing from the result.
+func ScanChartWithConfig(rows *sqlx.Rows, config *ChartConfig) ([]ChartDataRow, error) {
+	return ScanDynamicChartOptions(rows, ScanOptions{Config: config})
+}
+
+// seriesColumns returns the result column index of x_value and of each Y
+// series of config, with the series aliases, unquoted. Postgres folds
+// unquoted aliases to lower case, so names match case-insensitively.
+func seriesColumns(columns []string, config *ChartConfig) (int, []int, []string, error) {
+	x := findColumn(columns, "x_value")
+	if x < 0 {
+		return 0, nil, nil, fmt.Errorf("result has no x_value column: %s", strings.Join(columns, ", "))
+	}
+	indexes := make([]int, len(config.YAxis))
+	series := make([]string, len(config.YAxis))
+	for i, yAxis := range config.YAxis {
+		series[i] = unquoteIdent(yAlias(i, yAxis))
+		if indexes[i] = findColumn(columns, series[i]); indexes[i] < 0 {
+			return 0, nil, nil, fmt.Errorf("result has no column for y_axis series '%s': %s", series[i], strings.Join(columns, ", "))
+		}
+	}
+	return x, indexes, series, nil
+}
+
+// unquoteIdent returns a quoted alias such as "North" or `North` as the
+// column name the driver reports, North
+func unquoteIdent(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '`' && s[len(s)-1] == '`') {
+		quote := s[:1]
+		return strings.ReplaceAll(s[1:len(s)-1], quote+quote, quote)
+	}
+	return s
+}
+
+// findColumn returns the index of the column called name, preferring an exact
+// match over a case-insensitive one, or -1
+func findColumn(columns []string, name string) int {
+	for i, column := range columns {
+		if column == name {
+			return i
+		}
+	}
+	for i, column := range columns {
+		if strings.EqualFold(column, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ScanDynamicChartOptions is ScanDynamicChart with scan options
+func ScanDynamicChartOptions(rows *sqlx.Rows, opts ScanOptions) ([]ChartDataRow, error) {
 	// Get column information
 	columns, err := rows.Columns()
 	if err != nil {
 		return nil, err
 	}
 
+	var decimalColumns []bool
+	if opts.ExactDecimals {
+		columnTypes, err := rows.ColumnTypes()
+		if err != nil {
+			return nil, err
+		}
+		decimalColumns = make([]bool, len(columnTypes))
+		for i, columnType := range columnTypes {
+			switch strings.ToUpper(columnType.DatabaseTypeName()) {
+			case "NUMERIC", "DECIMAL":
+				decimalColumns[i] = true
+			}
+		}
+	}
+	max := opts.MaxRows
+
+	// Positional by default: X first, then the Y series in select order
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("chart query returned no columns")
+	}
+	// Without a config a total_count column is an ordinary series; only a
+	// config setting IncludeTotalCount makes it the row count
+	total := -1
+	x, yIndexes, series := 0, make([]int, len(columns)-1), columns[1:]
+	for i := range yIndexes {
+		yIndexes[i] = i + 1
+	}
+	if opts.Config != nil {
+		x, yIndexes, series, err = seriesColumns(columns, opts.Config)
+		if err != nil {
+			return nil, err
+		}
+		total = -1
+		if opts.Config.IncludeTotalCount {
+			if total = findColumn(columns, TotalCountColumn); total < 0 {
+				return nil, fmt.Errorf("result has no %s column: %s", TotalCountColumn, strings.Join(columns, ", "))
+			}
+		}
+	}
+
 	var results []ChartDataRow
 
-	for rows.Next() {
+	for (max <= 0 || len(results) < max) && rows.Next() {
 		// Create slice to hold all values (x + all y values)
 		values := make([]interface{}, len(columns))
 		valuePtrs := make([]interface{}, len(columns))
@@ -59,58 +279,84 @@ func ScanDynamicChart(rows *sqlx.Rows) ([]ChartDataRow, error) {
 
 		// Convert byte arrays to appropriate types if needed
 		for i, val := range values {
+			if decimalColumns != nil && decimalColumns[i] {
+				values[i], err = convertDecimal(val)
+				if err != nil {
+					return nil, fmt.Errorf("failed to scan column %s as decimal: %w", columns[i], err)
+				}
+				continue
+			}
 			values[i] = convertValue(val)
 		}
 
 		// Build the result row
-		row := ChartDataRow{
-			XValue:  values[0],  // First column is always x_value
-			YValues: values[1:], // Rest are y_values
+		yValues := make(map[string]interface{}, len(series))
+		for i, index := range yIndexes {
+			yValues[series[i]] = values[index]
 		}
-		yValues := map[string]interface{}{}
-		for i, v := range values[1:] {
-			yValues[columns[i+1]] = v
+		row := ChartDataRow{XValue: values[x], YValues: yValues, Series: series}
+		if total >= 0 {
+			if row.TotalCount, err = totalCount(values[total]); err != nil {
+				return nil, err
+			}
 		}
-		row.YValues = yValues
 		results = append(results, row)
 	}
 
+	// rows.Err reports an error that ended iteration or, when stopping early,
+	// one the driver hit while reading the rows scanned so far
 	return results, rows.Err()
 }
 
-// convertValue converts database values to appropriate Go types
-func convertValue(val interface{}) interface{} {
-	if val == nil {
-		return nil
-	}
-
-	// Handle different database driver value types
+// totalCount converts a scanned total_count value to an int64
+func totalCount(val interface{}) (int64, error) {
 	switch v := val.(type) {
-	case []byte:
-		// Convert byte slice to string
-		return string(v)
-	case driver.Value:
-		return v
+	case nil:
+		return 0, nil
 	case int64:
-		return float64(v) // Convert integers to float64 for consistency
+		return v, nil
 	case int32:
-		return float64(v)
+		return int64(v), nil
 	case int:
-		return float64(v)
-	case float32:
-		return float64(v)
+		return int64(v), nil
 	case float64:
-		return v
+		return int64(v), nil
 	case string:
-		return v
-	case time.Time:
-		return v
-	case bool:
-		if v {
-			return float64(1)
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scan %s: %w", TotalCountColumn, err)
 		}
-		return float64(0)
+		return n, nil
+	default:
+		return 0, fmt.Errorf("failed to scan %s: unexpected type %T", TotalCountColumn, val)
+	}
+}
+
+// convertDecimal converts a NUMERIC/DECIMAL column value to decimal.Decimal
+func convertDecimal(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return decimal.NewFromString(string(v))
+	case string:
+		return decimal.NewFromString(v)
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case int64:
+		return decimal.NewFromInt(v), nil
 	default:
-		return v
+		return decimal.NewFromString(fmt.Sprint(v))
+	}
+}
+
+// convertValue converts database values to appropriate Go types. Byte
+// slices become strings; everything else, numbers and booleans included, is
+// kept as the driver scanned it. NULL stays nil, so a NULL aggregate, such as
+// SUM over only NULLs, is never confused with a zero such as COUNT of no rows.
+func convertValue(val interface{}) interface{} {
+	if v, ok := val.([]byte); ok {
+		return string(v)
 	}
+	return val
 }
@@ -0,0 +1,99 @@
+//go:build integration
+
+package chatabase_test
+
+import (
+	"testing"
+
+	"github.com/midedickson/chatabase"
+)
+
+func TestNumericCastScansAsFloat64(t *testing.T) {
+	config, err := byRegion("cast").
+		Y("price", chatabase.WithAgg("SUM"), chatabase.WithCast("double precision")).
+		Build()
+	if err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	rows, err := chatabase.ExecuteChart(db, config, nil)
+	if err != nil {
+		t.Fatalf("ExecuteChart() error = %v", err)
+	}
+
+	want := map[string]float64{"east": 70.75, "north": 150.5, "south": 231}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for _, row := range rows {
+		value := row.YValues.(map[string]interface{})[row.Series[0]]
+		f, ok := value.(float64)
+		if !ok {
+			t.Errorf("%v: SUM(price) scanned as %T, want float64", row.XValue, value)
+			continue
+		}
+		if f != want[row.XValue.(string)] {
+			t.Errorf("%v: SUM(price) = %v, want %v", row.XValue, f, want[row.XValue.(string)])
+		}
+	}
+}
+
+func TestEmptyGroupAggregatesScanAsNilAndZero(t *testing.T) {
+	config, err := byRegion("refunds").
+		Y("amount", chatabase.WithAgg("SUM"), chatabase.WithSeriesFilter("status", "=", "refunded")).
+		Y("amount", chatabase.WithAgg("COUNT"), chatabase.WithSeriesFilter("status", "=", "refunded")).
+		Build()
+	if err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	rows, err := chatabase.ExecuteChart(db, config, nil)
+	if err != nil {
+		t.Fatalf("ExecuteChart() error = %v", err)
+	}
+
+	// Only south has a refunded order: SUM over no rows is NULL, COUNT is 0
+	want := map[string][2]interface{}{
+		"east":  {nil, int64(0)},
+		"north": {nil, int64(0)},
+		"south": {float64(30), int64(1)},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for _, row := range rows {
+		values := row.YValues.(map[string]interface{})
+		region := row.XValue.(string)
+		for i, value := range want[region] {
+			if got := values[row.Series[i]]; got != value {
+				t.Errorf("%s: %s = %#v, want %#v", region, row.Series[i], got, value)
+			}
+		}
+		if f := row.GetYValueAsFloat(0); (f == nil) != (want[region][0] == nil) {
+			t.Errorf("%s: GetYValueAsFloat(0) = %v, want nil only for a NULL SUM", region, f)
+		}
+	}
+}
+
+func TestTotalCountWithoutConfigIsASeries(t *testing.T) {
+	rows, err := db.Queryx(`SELECT region AS x_value, COUNT(*) AS total_count
+		FROM chatabase_it.orders GROUP BY region ORDER BY region`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	// Without a config nothing says the column is the include_total_count one
+	scanned, err := chatabase.ScanDynamicChart(rows)
+	if err != nil {
+		t.Fatalf("ScanDynamicChart() error = %v", err)
+	}
+	if len(scanned) == 0 {
+		t.Fatal("got no rows")
+	}
+	for _, row := range scanned {
+		if _, ok := row.YValues.(map[string]interface{})["total_count"]; !ok || row.TotalCount != 0 {
+			t.Errorf("%v: series %v, TotalCount %d, want total_count as a series", row.XValue, row.Series, row.TotalCount)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package chatabase
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetYValueAsFloat(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	row := ChartDataRow{
+		YValues: map[string]interface{}{
+			"null":    nil,
+			"zero":    int64(0),
+			"count":   int64(3),
+			"sum":     12.5,
+			"numeric": "7.25",
+			"paid":    true,
+			"unpaid":  false,
+			"label":   "north",
+		},
+		Series: []string{"null", "zero", "count", "sum", "numeric", "paid", "unpaid", "label"},
+	}
+	want := []*float64{nil, f(0), f(3), f(12.5), f(7.25), f(1), f(0), nil}
+
+	for i, w := range want {
+		got := row.GetYValueAsFloat(i)
+		if (got == nil) != (w == nil) || got != nil && *got != *w {
+			t.Errorf("GetYValueAsFloat(%d) for %s = %v, want %v", i, row.Series[i], got, w)
+		}
+	}
+	if got := row.GetYValueAsFloat(len(want)); got != nil {
+		t.Errorf("GetYValueAsFloat() out of range = %v, want nil", *got)
+	}
+}
+
+func TestConvertValueKeepsNullAndTypes(t *testing.T) {
+	for _, v := range []interface{}{nil, int64(0), int64(5), 2.5, true, false, "north"} {
+		if got := convertValue(v); got != v {
+			t.Errorf("convertValue(%#v) = %#v", v, got)
+		}
+	}
+	if got := convertValue([]byte("12.50")); got != "12.50" {
+		t.Errorf("convertValue([]byte) = %#v, want \"12.50\"", got)
+	}
+}
+
+func TestToColumnarBooleanAndNullSeries(t *testing.T) {
+	rows := []ChartDataRow{
+		{XValue: "north", YValues: map[string]interface{}{"sum": nil, "paid": true}, Series: []string{"sum", "paid"}},
+		{XValue: "south", YValues: map[string]interface{}{"sum": int64(30), "paid": false}, Series: []string{"sum", "paid"}},
+	}
+
+	categories, series, err := ToColumnar(rows)
+	if err != nil {
+		t.Fatalf("ToColumnar() error = %v", err)
+	}
+	if len(categories) != 2 || categories[0] != "north" || categories[1] != "south" {
+		t.Errorf("categories = %v, want [north south]", categories)
+	}
+	if !math.IsNaN(series[0][0]) || series[0][1] != 30 {
+		t.Errorf("sum series = %v, want [NaN 30]", series[0])
+	}
+	if series[1][0] != 1 || series[1][1] != 0 {
+		t.Errorf("paid series = %v, want [1 0]", series[1])
+	}
+}
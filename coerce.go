@@ -0,0 +1,169 @@
+package chatabase
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coerceTimeLayouts are the string formats accepted for date and timestamp columns
+var coerceTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// CoerceFilterValues converts filter values to the Go types that match their
+// target columns, so JSON-decoded values bind cleanly: whole float64s become
+// int64 for integer columns, numeric strings become numbers, date strings
+// become time.Time and "true"/"false" become bool. Both the filters and each
+// Y axis's series_filter are coerced. Columns are matched by name, ignoring
+// any table qualifier; filters on unknown columns, JSON paths and raw filters
+// are left untouched. Run it before BuildChartQuery.
+func CoerceFilterValues(config *ChartConfig, columns []ColumnInfo) error {
+	types := make(map[string]string, len(columns))
+	for _, column := range columns {
+		types[column.Name] = column.DataType
+	}
+
+	lookup := func(column string) (string, bool) {
+		if i := strings.LastIndex(column, "."); i >= 0 {
+			column = column[i+1:]
+		}
+		pgType, ok := types[column]
+		return pgType, ok
+	}
+
+	// Having filters compare aggregates, not columns, so they are left alone
+	lists := []namedFilters{{"filter", config.Filters}}
+	for i, yAxis := range config.YAxis {
+		lists = append(lists, namedFilters{fmt.Sprintf("y_axis[%d].series_filter", i), yAxis.SeriesFilter})
+	}
+	for _, list := range lists {
+		for i := range list.filters {
+			if err := coerceFilter(&list.filters[i], lookup); err != nil {
+				return fmt.Errorf("%s at index %d, %w", list.name, i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// coerceFilter coerces the values of one filter, looking column types up with lookup
+func coerceFilter(filter *FilterConfig, lookup func(string) (string, bool)) error {
+	if filter.Raw != "" || len(filter.JSONPath) > 0 {
+		return nil
+	}
+
+	if len(filter.Columns) > 0 {
+		for j, column := range filter.Columns {
+			pgType, ok := lookup(column)
+			if !ok {
+				continue
+			}
+			for k, tuple := range filter.Tuples {
+				if j >= len(tuple) {
+					continue
+				}
+				v, err := coerceValue(tuple[j], pgType)
+				if err != nil {
+					return fmt.Errorf("tuple %d, column %s: %w", k, column, err)
+				}
+				tuple[j] = v
+			}
+		}
+		return nil
+	}
+
+	pgType, ok := lookup(filter.Column)
+	if !ok {
+		return nil
+	}
+
+	v, err := coerceValue(filter.Value, pgType)
+	if err != nil {
+		return fmt.Errorf("column %s: %w", filter.Column, err)
+	}
+	filter.Value = v
+
+	for j := range filter.Values {
+		v, err := coerceValue(filter.Values[j], pgType)
+		if err != nil {
+			return fmt.Errorf("column %s, value %d: %w", filter.Column, j, err)
+		}
+		filter.Values[j] = v
+	}
+	return nil
+}
+
+// coerceValue converts a single value to match a raw Postgres column type.
+// NULLs and types it has no rule for pass through unchanged.
+func coerceValue(v interface{}, pgType string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if isIntegerType(pgType) {
+		switch val := v.(type) {
+		case float64:
+			if val != math.Trunc(val) {
+				return nil, fmt.Errorf("cannot coerce %v to integer column type %s", val, pgType)
+			}
+			return int64(val), nil
+		case string:
+			n, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot coerce %q to integer column type %s", val, pgType)
+			}
+			return n, nil
+		}
+		return v, nil
+	}
+
+	switch ClassifyDataType(pgType) {
+	case DataTypeNumeric:
+		if s, ok := v.(string); ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot coerce %q to numeric column type %s", s, pgType)
+			}
+			return f, nil
+		}
+
+	case DataTypeDatetime:
+		// Time-of-day columns are compared as text by Postgres
+		if strings.HasPrefix(normalizePgType(pgType), "time") && !strings.HasPrefix(normalizePgType(pgType), "timestamp") {
+			return v, nil
+		}
+		if s, ok := v.(string); ok {
+			for _, layout := range coerceTimeLayouts {
+				if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("cannot coerce %q to date/time column type %s", s, pgType)
+		}
+
+	case DataTypeBoolean:
+		switch val := v.(type) {
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(val))
+			if err != nil {
+				return nil, fmt.Errorf("cannot coerce %q to boolean column type %s", val, pgType)
+			}
+			return b, nil
+		case float64:
+			if val != 0 && val != 1 {
+				return nil, fmt.Errorf("cannot coerce %v to boolean column type %s", val, pgType)
+			}
+			return val == 1, nil
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,75 @@
+package chatabase
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCoerceFilterValuesWithSeriesFilters(t *testing.T) {
+	columns := []ColumnInfo{
+		{Name: "quantity", DataType: "integer"},
+		{Name: "created_at", DataType: "timestamp with time zone"},
+		{Name: "paid", DataType: "boolean"},
+		{Name: "amount", DataType: "numeric"},
+	}
+
+	// Values as a JSON request body decodes them
+	config := testConfig(
+		FilterConfig{Column: "o.quantity", Operator: ">", Value: float64(2)},
+		FilterConfig{Column: "o.created_at", Operator: ">=", Value: "2024-01-01"},
+	)
+	config.YAxis[0].SeriesFilter = []FilterConfig{
+		{Column: "o.paid", Operator: "=", Value: "true"},
+		{Column: "o.quantity", Operator: "IN", Values: []interface{}{float64(1), "3"}},
+	}
+	config.YAxis = append(config.YAxis, AxisConfig{
+		Column:       "o.amount",
+		Aggregation:  "SUM",
+		SeriesFilter: []FilterConfig{{Column: "o.amount", Operator: ">", Value: "9.5"}},
+	})
+
+	if err := CoerceFilterValues(config, columns); err != nil {
+		t.Fatalf("CoerceFilterValues() error = %v", err)
+	}
+
+	checks := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"filters[0]", config.Filters[0].Value, int64(2)},
+		{"filters[1]", config.Filters[1].Value, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"y_axis[0].series_filter[0]", config.YAxis[0].SeriesFilter[0].Value, true},
+		{"y_axis[0].series_filter[1]", config.YAxis[0].SeriesFilter[1].Values, []interface{}{int64(1), int64(3)}},
+		{"y_axis[1].series_filter[0]", config.YAxis[1].SeriesFilter[0].Value, 9.5},
+	}
+	for _, check := range checks {
+		if !reflect.DeepEqual(check.got, check.want) {
+			t.Errorf("%s = %#v, want %#v", check.name, check.got, check.want)
+		}
+	}
+
+	query, args, err := BuildChartQuery(config)
+	if err != nil {
+		t.Fatalf("BuildChartQuery() error = %v", err)
+	}
+	if !strings.Contains(query, "FILTER (WHERE") {
+		t.Errorf("query %q has no series FILTER clause", query)
+	}
+	wantArgs := []interface{}{int64(1), int64(3), 9.5, int64(2), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %#v, want %#v", args, wantArgs)
+	}
+}
+
+func TestCoerceFilterValuesSeriesFilterError(t *testing.T) {
+	config := testConfig()
+	config.YAxis[0].SeriesFilter = []FilterConfig{{Column: "o.quantity", Operator: "=", Value: "many"}}
+
+	err := CoerceFilterValues(config, []ColumnInfo{{Name: "quantity", DataType: "integer"}})
+	if err == nil || !strings.HasPrefix(err.Error(), "y_axis[0].series_filter at index 0, column o.quantity") {
+		t.Errorf("CoerceFilterValues() error = %v, want one naming y_axis[0].series_filter", err)
+	}
+}
@@ -0,0 +1,72 @@
+package chatabase
+
+import (
+	"fmt"
+	"math"
+)
+
+// ColumnarOptions controls ToColumnarWithOptions
+type ColumnarOptions struct {
+	// NullAsZero fills NULL cells with 0 instead of NaN. NaN leaves a gap in
+	// most line charts; 0 draws the point on the axis.
+	NullAsZero bool
+}
+
+// ToColumnar transposes scanned rows into the shape most charting libraries
+// consume: the X values as categories and one []float64 per Y series, in
+// series order, each parallel to categories. NULL cells become NaN. It fails
+// when the rows have different series or a cell is not numeric.
+func ToColumnar(rows []ChartDataRow) ([]interface{}, [][]float64, error) {
+	return ToColumnarWithOptions(rows, ColumnarOptions{})
+}
+
+// ToColumnarWithOptions is ToColumnar with options choosing the NULL filler
+func ToColumnarWithOptions(rows []ChartDataRow, opts ColumnarOptions) ([]interface{}, [][]float64, error) {
+	null := math.NaN()
+	if opts.NullAsZero {
+		null = 0
+	}
+
+	categories := make([]interface{}, len(rows))
+	if len(rows) == 0 {
+		return categories, [][]float64{}, nil
+	}
+	names := rows[0].Series
+	series := make([][]float64, len(names))
+	for i := range series {
+		series[i] = make([]float64, len(rows))
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if !sameSeries(row.Series, names) {
+			return nil, nil, fmt.Errorf("row %d has series %v but row 0 has %v", i, row.Series, names)
+		}
+		categories[i] = row.XValue
+		values, _ := row.YValues.(map[string]interface{})
+		for j, name := range names {
+			if f := row.GetYValueAsFloat(j); f != nil {
+				series[j][i] = *f
+				continue
+			}
+			if values[name] != nil {
+				return nil, nil, fmt.Errorf("series '%s' at row %d is not numeric: %v", name, i, values[name])
+			}
+			series[j][i] = null
+		}
+	}
+	return categories, series, nil
+}
+
+// sameSeries reports whether two rows have the same series in the same order
+func sameSeries(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
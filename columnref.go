@@ -0,0 +1,200 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnRef is a column referenced by a config expression. Table is the
+// alias or table name qualifying it, or empty for a bare column.
+type ColumnRef struct {
+	Table  string
+	Column string
+}
+
+// String returns the reference as written, e.g. "o.amount"
+func (r ColumnRef) String() string {
+	if r.Table == "" {
+		return r.Column
+	}
+	return r.Table + "." + r.Column
+}
+
+// sqlKeywords are words that may appear unquoted in config expressions
+// without referencing a column, such as the date parts of EXTRACT
+var sqlKeywords = map[string]bool{
+	"and": true, "or": true, "not": true, "null": true, "true": true, "false": true,
+	"is": true, "in": true, "like": true, "ilike": true, "between": true, "exists": true,
+	"case": true, "when": true, "then": true, "else": true, "end": true,
+	"as": true, "distinct": true, "all": true, "any": true, "some": true, "from": true,
+	"interval": true, "over": true, "partition": true, "by": true, "order": true,
+	"asc": true, "desc": true, "nulls": true, "first": true, "last": true,
+	"filter": true, "where": true, "within": true, "group": true,
+	"year": true, "quarter": true, "month": true, "week": true, "day": true,
+	"hour": true, "minute": true, "second": true, "epoch": true, "dow": true, "doy": true,
+}
+
+// expressionRefs returns the columns an SQL expression references. String
+// literals, numbers, placeholders, function names, keywords and the type
+// names of casts are skipped; a.b.c is read as table b, column c.
+func expressionRefs(expr string) ([]ColumnRef, error) {
+	var refs []ColumnRef
+	skipType := false // the next identifiers name a type, after "::" or AS
+	depth := 0        // open parentheses
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '\'':
+			j := i + 1
+			for {
+				if j >= len(expr) {
+					return nil, fmt.Errorf("unterminated string literal in '%s'", expr)
+				}
+				if expr[j] == '\'' {
+					if j+1 < len(expr) && expr[j+1] == '\'' {
+						// '' escapes a quote inside the literal
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			i = j + 1
+			skipType = false
+
+		case c == ':' && i+1 < len(expr) && expr[i+1] == ':':
+			i += 2
+			skipType = true
+
+		case c == '"' || c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			var parts []string
+			quoted := false
+			for {
+				part, n, wasQuoted, err := readIdentifier(expr[i:])
+				if err != nil {
+					return nil, fmt.Errorf("%w in '%s'", err, expr)
+				}
+				parts = append(parts, part)
+				quoted = quoted || wasQuoted
+				i += n
+				if i+1 < len(expr) && expr[i] == '.' && expr[i+1] != '.' {
+					i++
+					if expr[i] == '*' {
+						// t.* selects every column of t
+						i++
+						parts = nil
+						break
+					}
+					continue
+				}
+				break
+			}
+			if len(parts) == 0 {
+				continue
+			}
+
+			if skipType {
+				continue
+			}
+			word := parts[0]
+			switch {
+			case strings.HasPrefix(strings.TrimLeft(expr[i:], " \t\n\r"), "("):
+				// A function call such as SUM(amount)
+			case len(parts) == 1 && !quoted && word == "as":
+				skipType = true
+			case len(parts) == 1 && !quoted && sqlKeywords[word]:
+			default:
+				ref := ColumnRef{Column: parts[len(parts)-1]}
+				if len(parts) > 1 {
+					ref.Table = parts[len(parts)-2]
+				}
+				refs = append(refs, ref)
+			}
+
+		default:
+			// Numbers, operators, placeholders and punctuation end a type name
+			i++
+			skipType = false
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth < 0 {
+					return nil, fmt.Errorf("unbalanced ')' in '%s'", expr)
+				}
+			case ';':
+				return nil, fmt.Errorf("unexpected ';' in '%s'", expr)
+			}
+			if c >= '0' && c <= '9' || c == '$' || c == '?' {
+				for i < len(expr) && (expr[i] >= '0' && expr[i] <= '9' || expr[i] == '.') {
+					i++
+				}
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced '(' in '%s'", expr)
+	}
+
+	return refs, nil
+}
+
+// readIdentifier reads one plain or double-quoted identifier from the start
+// of s, returning it, the bytes consumed and whether it was quoted. Plain
+// identifiers are folded to lower case, as Postgres does.
+func readIdentifier(s string) (string, int, bool, error) {
+	if s[0] == '"' {
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			if s[i] != '"' {
+				b.WriteByte(s[i])
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '"' {
+				b.WriteByte('"')
+				i++
+				continue
+			}
+			return b.String(), i + 1, true, nil
+		}
+		return "", 0, false, fmt.Errorf("unterminated quoted identifier")
+	}
+
+	n := 0
+	for n < len(s) {
+		c := s[n]
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || n > 0 && (c >= '0' && c <= '9' || c == '$') {
+			n++
+			continue
+		}
+		break
+	}
+	return strings.ToLower(s[:n]), n, false, nil
+}
+
+// ParseJoinCondition returns the columns a join condition references, e.g.
+// [u.id o.user_id] for "u.id = o.user_id". ANDed conditions, function calls
+// and casts are understood: "lower(u.email) = o.email AND u.active" yields
+// u.email, o.email and u.active. Bare columns are returned with an empty
+// Table. Conditions that are empty, reference no columns, or have
+// unbalanced quotes or parentheses are errors.
+func ParseJoinCondition(cond string) ([]ColumnRef, error) {
+	if strings.TrimSpace(cond) == "" {
+		return nil, fmt.Errorf("join condition is empty")
+	}
+
+	refs, err := expressionRefs(cond)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse join condition: %w", err)
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("join condition '%s' references no columns", cond)
+	}
+	return refs, nil
+}
@@ -0,0 +1,61 @@
+package chatabase
+
+import "strings"
+
+// Chart data type categories used by AxisConfig.DataType
+const (
+	DataTypeNumeric  = "numeric"
+	DataTypeDatetime = "datetime"
+	DataTypeString   = "string"
+	DataTypeBoolean  = "boolean"
+)
+
+// ClassifyDataType maps a raw Postgres type name, as found in
+// ColumnInfo.DataType, to a chart data type category. Type modifiers such as
+// "(10,2)" are ignored, arrays and unknown types are treated as strings.
+func ClassifyDataType(pgType string) string {
+	t := normalizePgType(pgType)
+
+	// Arrays: information_schema reports "ARRAY", format_type appends "[]"
+	// and udt names are prefixed with "_"
+	if t == "array" || strings.HasSuffix(t, "[]") || strings.HasPrefix(t, "_") {
+		return DataTypeString
+	}
+
+	switch t {
+	case "smallint", "integer", "bigint", "int", "int2", "int4", "int8",
+		"smallserial", "serial", "bigserial", "serial2", "serial4", "serial8",
+		"decimal", "numeric", "real", "double precision", "float", "float4", "float8",
+		"money", "oid":
+		return DataTypeNumeric
+	case "timestamp", "timestamp without time zone", "timestamp with time zone", "timestamptz",
+		"date", "time", "time without time zone", "time with time zone", "timetz":
+		return DataTypeDatetime
+	case "boolean", "bool":
+		return DataTypeBoolean
+	default:
+		return DataTypeString
+	}
+}
+
+// isIntegerType reports whether a raw Postgres type holds whole numbers only
+func isIntegerType(pgType string) bool {
+	switch normalizePgType(pgType) {
+	case "smallint", "integer", "bigint", "int", "int2", "int4", "int8",
+		"smallserial", "serial", "bigserial", "serial2", "serial4", "serial8", "oid":
+		return true
+	}
+	return false
+}
+
+// normalizePgType lowercases a type name and strips type modifiers:
+// "NUMERIC(10,2)" -> "numeric", "timestamp(3) with time zone" -> "timestamp with time zone"
+func normalizePgType(pgType string) string {
+	t := strings.ToLower(strings.TrimSpace(pgType))
+	if i := strings.Index(t, "("); i >= 0 {
+		if j := strings.Index(t[i:], ")"); j >= 0 {
+			t = strings.TrimSpace(t[:i] + t[i+j+1:])
+		}
+	}
+	return strings.Join(strings.Fields(t), " ")
+}
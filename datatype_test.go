@@ -0,0 +1,49 @@
+package chatabase
+
+import "testing"
+
+func TestClassifyDataType(t *testing.T) {
+	tests := []struct {
+		pgType string
+		want   string
+	}{
+		{"smallint", DataTypeNumeric},
+		{"integer", DataTypeNumeric},
+		{"bigint", DataTypeNumeric},
+		{"int4", DataTypeNumeric},
+		{"bigserial", DataTypeNumeric},
+		{"numeric", DataTypeNumeric},
+		{"NUMERIC(10,2)", DataTypeNumeric},
+		{"decimal", DataTypeNumeric},
+		{"real", DataTypeNumeric},
+		{"double precision", DataTypeNumeric},
+		{"float8", DataTypeNumeric},
+		{"money", DataTypeNumeric},
+		{"timestamp without time zone", DataTypeDatetime},
+		{"timestamp with time zone", DataTypeDatetime},
+		{"timestamp(3) with time zone", DataTypeDatetime},
+		{"timestamptz", DataTypeDatetime},
+		{"date", DataTypeDatetime},
+		{"time without time zone", DataTypeDatetime},
+		{"timetz", DataTypeDatetime},
+		{"boolean", DataTypeBoolean},
+		{"bool", DataTypeBoolean},
+		{"character varying", DataTypeString},
+		{"character varying(255)", DataTypeString},
+		{"text", DataTypeString},
+		{"uuid", DataTypeString},
+		{"jsonb", DataTypeString},
+		{"ARRAY", DataTypeString},
+		{"integer[]", DataTypeString},
+		{"_int4", DataTypeString},
+		{"interval", DataTypeString},
+		{"order_status", DataTypeString},
+		{"", DataTypeString},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyDataType(tt.pgType); got != tt.want {
+			t.Errorf("ClassifyDataType(%q) = %q, want %q", tt.pgType, got, tt.want)
+		}
+	}
+}
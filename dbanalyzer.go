@@ -1,8 +1,13 @@
 package chatabase
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+
 	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/errgroup"
 )
 
 // ColumnInfo represents detailed information about a database column
@@ -55,19 +60,54 @@ type DomainInfo struct {
 	Description  *string `db:"description"`
 }
 
+// CheckConstraint represents a CHECK constraint on a table
+type CheckConstraint struct {
+	TableName      string
+	ConstraintName string
+	Columns        []string // Columns the constraint references, in column order
+	Definition     string   // e.g. "CHECK ((amount >= (0)::numeric))"
+}
+
+// TableInfo is a table with its columns, as returned by GetTablesWithColumns
 type TableInfo struct {
 	Name    string
 	Columns []ColumnInfo
 }
 
+// SchemaSnapshot combines the tables, columns and custom types of a schema
+// into a single object for the chart builder to introspect
+type SchemaSnapshot struct {
+	Schema      string
+	Tables      []TableInfo
+	CustomTypes map[string]interface{} // as returned by GetCustomTypesWithDetails
+}
+
+// snapshotConcurrency bounds the column queries GetTablesWithColumns runs at once
+const snapshotConcurrency = 8
+
 func GetTablesPostgreSQL(db *sqlx.DB) ([]string, error) {
+	return GetTablesPostgreSQLContext(context.Background(), db)
+}
+
+// GetTablesPostgreSQLContext is GetTablesPostgreSQL with a context for cancellation and timeouts
+func GetTablesPostgreSQLContext(ctx context.Context, db *sqlx.DB) ([]string, error) {
+	return GetSchemaTablesContext(ctx, db, "public")
+}
+
+// GetSchemaTables returns the tables in a schema, ordered by name
+func GetSchemaTables(db *sqlx.DB, schemaName string) ([]string, error) {
+	return GetSchemaTablesContext(context.Background(), db, schemaName)
+}
+
+// GetSchemaTablesContext is GetSchemaTables with a context for cancellation and timeouts
+func GetSchemaTablesContext(ctx context.Context, db *sqlx.DB, schemaName string) ([]string, error) {
 	query := `
         SELECT tablename 
         FROM pg_tables 
-        WHERE schemaname = 'public'
+        WHERE schemaname = $1
         ORDER BY tablename`
 
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +126,21 @@ func GetTablesPostgreSQL(db *sqlx.DB) ([]string, error) {
 }
 
 func GetColumnInfoPostgreSQL(db *sqlx.DB, tableName string) ([]ColumnInfo, error) {
+	return GetColumnInfoPostgreSQLContext(context.Background(), db, tableName)
+}
+
+// GetColumnInfoPostgreSQLContext is GetColumnInfoPostgreSQL with a context for cancellation and timeouts
+func GetColumnInfoPostgreSQLContext(ctx context.Context, db *sqlx.DB, tableName string) ([]ColumnInfo, error) {
+	return GetSchemaColumnInfoContext(ctx, db, "public", tableName)
+}
+
+// GetSchemaColumnInfo returns the columns of a table in a schema, ordered by position
+func GetSchemaColumnInfo(db *sqlx.DB, schemaName, tableName string) ([]ColumnInfo, error) {
+	return GetSchemaColumnInfoContext(context.Background(), db, schemaName, tableName)
+}
+
+// GetSchemaColumnInfoContext is GetSchemaColumnInfo with a context for cancellation and timeouts
+func GetSchemaColumnInfoContext(ctx context.Context, db *sqlx.DB, schemaName, tableName string) ([]ColumnInfo, error) {
 	query := `
 		SELECT 
 			c.column_name,
@@ -105,24 +160,141 @@ func GetColumnInfoPostgreSQL(db *sqlx.DB, tableName string) ([]ColumnInfo, error
 				AND tc.table_schema = ku.table_schema
 			WHERE tc.constraint_type = 'PRIMARY KEY'
 				AND tc.table_name = $1
-				AND tc.table_schema = 'public'
+				AND tc.table_schema = $2
 		) pk ON c.column_name = pk.column_name
 		LEFT JOIN pg_catalog.pg_statio_all_tables st 
 			ON c.table_name = st.relname
+			AND c.table_schema = st.schemaname
 		LEFT JOIN pg_catalog.pg_description pgd 
 			ON pgd.objoid = st.relid 
 			AND pgd.objsubid = c.ordinal_position
 		WHERE c.table_name = $1 
-			AND c.table_schema = 'public'
+			AND c.table_schema = $2
 		ORDER BY c.ordinal_position`
 
 	var columns []ColumnInfo
-	err := db.Select(&columns, query, tableName)
+	if err := db.SelectContext(ctx, &columns, query, tableName, schemaName); err != nil {
+		return nil, err
+	}
+
+	// information_schema.columns covers tables and views but not materialized views
+	if len(columns) == 0 {
+		return getMaterializedViewColumns(ctx, db, schemaName, tableName)
+	}
+
+	return columns, nil
+}
+
+// getMaterializedViewColumns reads materialized view columns from pg_attribute
+func getMaterializedViewColumns(ctx context.Context, db *sqlx.DB, schemaName, viewName string) ([]ColumnInfo, error) {
+	query := `
+		SELECT 
+			a.attname as column_name,
+			format_type(a.atttypid, NULL) as data_type,
+			CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END as is_nullable,
+			NULL::text as column_default,
+			NULL::int as character_maximum_length,
+			a.attnum as ordinal_position,
+			false as is_primary_key,
+			COALESCE(col_description(c.oid, a.attnum), '') as column_comment
+		FROM pg_class c
+		JOIN pg_namespace n ON c.relnamespace = n.oid
+		JOIN pg_attribute a ON a.attrelid = c.oid
+		WHERE c.relname = $1
+			AND n.nspname = $2
+			AND c.relkind = 'm'
+			AND a.attnum > 0
+			AND NOT a.attisdropped
+		ORDER BY a.attnum`
+
+	var columns []ColumnInfo
+	err := db.SelectContext(ctx, &columns, query, viewName, schemaName)
 	return columns, err
 }
 
+// GetViews returns the views in a schema, ordered by name
+func GetViews(db *sqlx.DB, schemaName string) ([]string, error) {
+	return GetViewsContext(context.Background(), db, schemaName)
+}
+
+// GetViewsContext is GetViews with a context for cancellation and timeouts
+func GetViewsContext(ctx context.Context, db *sqlx.DB, schemaName string) ([]string, error) {
+	query := `
+		SELECT table_name
+		FROM information_schema.views
+		WHERE table_schema = $1
+		ORDER BY table_name`
+
+	var views []string
+	err := db.SelectContext(ctx, &views, query, schemaName)
+	return views, err
+}
+
+// GetMaterializedViews returns the materialized views in a schema, ordered by name
+func GetMaterializedViews(db *sqlx.DB, schemaName string) ([]string, error) {
+	return GetMaterializedViewsContext(context.Background(), db, schemaName)
+}
+
+// GetMaterializedViewsContext is GetMaterializedViews with a context for cancellation and timeouts
+func GetMaterializedViewsContext(ctx context.Context, db *sqlx.DB, schemaName string) ([]string, error) {
+	query := `
+		SELECT matviewname
+		FROM pg_matviews
+		WHERE schemaname = $1
+		ORDER BY matviewname`
+
+	var views []string
+	err := db.SelectContext(ctx, &views, query, schemaName)
+	return views, err
+}
+
+// ChartSourceOptions selects which kinds of relations GetChartSources lists
+type ChartSourceOptions struct {
+	IncludeViews             bool
+	IncludeMaterializedViews bool
+}
+
+// GetChartSources returns the relations in a schema that charts can read
+// from: its tables, plus views and materialized views when requested.
+// The result is sorted by name.
+func GetChartSources(db *sqlx.DB, schemaName string, opts ChartSourceOptions) ([]string, error) {
+	return GetChartSourcesContext(context.Background(), db, schemaName, opts)
+}
+
+// GetChartSourcesContext is GetChartSources with a context for cancellation and timeouts
+func GetChartSourcesContext(ctx context.Context, db *sqlx.DB, schemaName string, opts ChartSourceOptions) ([]string, error) {
+	sources, err := GetSchemaTablesContext(ctx, db, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tables: %w", err)
+	}
+
+	if opts.IncludeViews {
+		views, err := GetViewsContext(ctx, db, schemaName)
+		if err != nil {
+			return nil, fmt.Errorf("error getting views: %w", err)
+		}
+		sources = append(sources, views...)
+	}
+
+	if opts.IncludeMaterializedViews {
+		views, err := GetMaterializedViewsContext(ctx, db, schemaName)
+		if err != nil {
+			return nil, fmt.Errorf("error getting materialized views: %w", err)
+		}
+		sources = append(sources, views...)
+	}
+
+	sort.Strings(sources)
+	return sources, nil
+}
+
 // GetAllCustomTypes returns all custom types in the database
 func GetAllCustomTypes(db *sqlx.DB, schemaName string) ([]CustomType, error) {
+	return GetAllCustomTypesContext(context.Background(), db, schemaName)
+}
+
+// GetAllCustomTypesContext is GetAllCustomTypes with a context for cancellation and timeouts
+func GetAllCustomTypesContext(ctx context.Context, db *sqlx.DB, schemaName string) ([]CustomType, error) {
 	query := `
 		SELECT 
 			n.nspname as schema_name,
@@ -167,12 +339,17 @@ func GetAllCustomTypes(db *sqlx.DB, schemaName string) ([]CustomType, error) {
 		ORDER BY n.nspname, t.typname`
 
 	var types []CustomType
-	err := db.Select(&types, query, schemaName)
+	err := db.SelectContext(ctx, &types, query, schemaName)
 	return types, err
 }
 
 // GetEnumTypes returns all ENUM types with their values
 func GetEnumTypes(db *sqlx.DB, schemaName string) (map[string][]EnumValue, error) {
+	return GetEnumTypesContext(context.Background(), db, schemaName)
+}
+
+// GetEnumTypesContext is GetEnumTypes with a context for cancellation and timeouts
+func GetEnumTypesContext(ctx context.Context, db *sqlx.DB, schemaName string) (map[string][]EnumValue, error) {
 	query := `
 		SELECT 
 			t.typname as type_name,
@@ -185,7 +362,7 @@ func GetEnumTypes(db *sqlx.DB, schemaName string) (map[string][]EnumValue, error
 			AND t.typtype = 'e'
 		ORDER BY t.typname, e.enumsortorder`
 
-	rows, err := db.Query(query, schemaName)
+	rows, err := db.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -205,6 +382,11 @@ func GetEnumTypes(db *sqlx.DB, schemaName string) (map[string][]EnumValue, error
 
 // GetCompositeTypes returns all composite types with their attributes
 func GetCompositeTypes(db *sqlx.DB, schemaName string) (map[string][]CompositeTypeAttribute, error) {
+	return GetCompositeTypesContext(context.Background(), db, schemaName)
+}
+
+// GetCompositeTypesContext is GetCompositeTypes with a context for cancellation and timeouts
+func GetCompositeTypesContext(ctx context.Context, db *sqlx.DB, schemaName string) (map[string][]CompositeTypeAttribute, error) {
 	query := `
 		SELECT 
 			t.typname as type_name,
@@ -224,7 +406,7 @@ func GetCompositeTypes(db *sqlx.DB, schemaName string) (map[string][]CompositeTy
 			AND NOT a.attisdropped
 		ORDER BY t.typname, a.attnum`
 
-	rows, err := db.Query(query, schemaName)
+	rows, err := db.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -245,6 +427,11 @@ func GetCompositeTypes(db *sqlx.DB, schemaName string) (map[string][]CompositeTy
 
 // GetDomainTypes returns all domain types with their constraints
 func GetDomainTypes(db *sqlx.DB, schemaName string) ([]DomainInfo, error) {
+	return GetDomainTypesContext(context.Background(), db, schemaName)
+}
+
+// GetDomainTypesContext is GetDomainTypes with a context for cancellation and timeouts
+func GetDomainTypesContext(ctx context.Context, db *sqlx.DB, schemaName string) ([]DomainInfo, error) {
 	query := `
 		SELECT 
 			n.nspname as schema_name,
@@ -262,12 +449,17 @@ func GetDomainTypes(db *sqlx.DB, schemaName string) ([]DomainInfo, error) {
 		ORDER BY t.typname`
 
 	var domains []DomainInfo
-	err := db.Select(&domains, query, schemaName)
+	err := db.SelectContext(ctx, &domains, query, schemaName)
 	return domains, err
 }
 
 // GetRangeTypes returns all range types
 func GetRangeTypes(db *sqlx.DB, schemaName string) ([]CustomType, error) {
+	return GetRangeTypesContext(context.Background(), db, schemaName)
+}
+
+// GetRangeTypesContext is GetRangeTypes with a context for cancellation and timeouts
+func GetRangeTypesContext(ctx context.Context, db *sqlx.DB, schemaName string) ([]CustomType, error) {
 	query := `
 		SELECT 
 			n.nspname as schema_name,
@@ -283,44 +475,49 @@ func GetRangeTypes(db *sqlx.DB, schemaName string) ([]CustomType, error) {
 		ORDER BY t.typname`
 
 	var ranges []CustomType
-	err := db.Select(&ranges, query, schemaName)
+	err := db.SelectContext(ctx, &ranges, query, schemaName)
 	return ranges, err
 }
 
 // GetCustomTypesWithDetails returns all custom types with their detailed information
 func GetCustomTypesWithDetails(db *sqlx.DB, schemaName string) (map[string]interface{}, error) {
+	return GetCustomTypesWithDetailsContext(context.Background(), db, schemaName)
+}
+
+// GetCustomTypesWithDetailsContext is GetCustomTypesWithDetails with a context for cancellation and timeouts
+func GetCustomTypesWithDetailsContext(ctx context.Context, db *sqlx.DB, schemaName string) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
 	// Get all custom types
-	allTypes, err := GetAllCustomTypes(db, schemaName)
+	allTypes, err := GetAllCustomTypesContext(ctx, db, schemaName)
 	if err != nil {
 		return nil, fmt.Errorf("error getting custom types: %w", err)
 	}
 	result["all_types"] = allTypes
 
 	// Get enum types with values
-	enumTypes, err := GetEnumTypes(db, schemaName)
+	enumTypes, err := GetEnumTypesContext(ctx, db, schemaName)
 	if err != nil {
 		return nil, fmt.Errorf("error getting enum types: %w", err)
 	}
 	result["enum_types"] = enumTypes
 
 	// Get composite types with attributes
-	compositeTypes, err := GetCompositeTypes(db, schemaName)
+	compositeTypes, err := GetCompositeTypesContext(ctx, db, schemaName)
 	if err != nil {
 		return nil, fmt.Errorf("error getting composite types: %w", err)
 	}
 	result["composite_types"] = compositeTypes
 
 	// Get domain types
-	domainTypes, err := GetDomainTypes(db, schemaName)
+	domainTypes, err := GetDomainTypesContext(ctx, db, schemaName)
 	if err != nil {
 		return nil, fmt.Errorf("error getting domain types: %w", err)
 	}
 	result["domain_types"] = domainTypes
 
 	// Get range types
-	rangeTypes, err := GetRangeTypes(db, schemaName)
+	rangeTypes, err := GetRangeTypesContext(ctx, db, schemaName)
 	if err != nil {
 		return nil, fmt.Errorf("error getting range types: %w", err)
 	}
@@ -328,3 +525,111 @@ func GetCustomTypesWithDetails(db *sqlx.DB, schemaName string) (map[string]inter
 
 	return result, nil
 }
+
+// LoadSchemaSnapshot loads every table in a schema with its columns, plus the
+// schema's custom types, in one call
+func LoadSchemaSnapshot(db *sqlx.DB, schemaName string) (*SchemaSnapshot, error) {
+	return LoadSchemaSnapshotContext(context.Background(), db, schemaName)
+}
+
+// LoadSchemaSnapshotContext is LoadSchemaSnapshot with a context for cancellation and timeouts
+func LoadSchemaSnapshotContext(ctx context.Context, db *sqlx.DB, schemaName string) (*SchemaSnapshot, error) {
+	tables, err := GetTablesWithColumnsContext(ctx, db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	customTypes, err := GetCustomTypesWithDetailsContext(ctx, db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SchemaSnapshot{
+		Schema:      schemaName,
+		Tables:      tables,
+		CustomTypes: customTypes,
+	}, nil
+}
+
+// GetTablesWithColumns returns every table in a schema with its Columns
+// filled, ordered by table name and then column position
+func GetTablesWithColumns(db *sqlx.DB, schemaName string) ([]TableInfo, error) {
+	return GetTablesWithColumnsContext(context.Background(), db, schemaName)
+}
+
+// GetTablesWithColumnsContext is GetTablesWithColumns with a context for
+// cancellation and timeouts. Column queries run concurrently, at most
+// snapshotConcurrency at a time.
+func GetTablesWithColumnsContext(ctx context.Context, db *sqlx.DB, schemaName string) ([]TableInfo, error) {
+	names, err := GetSchemaTablesContext(ctx, db, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tables: %w", err)
+	}
+
+	tables := make([]TableInfo, len(names))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(snapshotConcurrency)
+	for i, name := range names {
+		g.Go(func() error {
+			columns, err := GetSchemaColumnInfoContext(gctx, db, schemaName, name)
+			if err != nil {
+				return fmt.Errorf("error getting columns for table %s: %w", name, err)
+			}
+			tables[i] = TableInfo{Name: name, Columns: columns}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// GetCheckConstraints returns the CHECK constraints of every table in a schema.
+// Definitions are returned raw, as produced by pg_get_constraintdef.
+func GetCheckConstraints(db *sqlx.DB, schemaName string) ([]CheckConstraint, error) {
+	return GetCheckConstraintsContext(context.Background(), db, schemaName)
+}
+
+// GetCheckConstraintsContext is GetCheckConstraints with a context for cancellation and timeouts
+func GetCheckConstraintsContext(ctx context.Context, db *sqlx.DB, schemaName string) ([]CheckConstraint, error) {
+	query := `
+		SELECT 
+			c.relname as table_name,
+			con.conname as constraint_name,
+			COALESCE((
+				SELECT json_agg(a.attname ORDER BY a.attnum)
+				FROM pg_attribute a
+				WHERE a.attrelid = con.conrelid
+					AND a.attnum = ANY(con.conkey)
+			), '[]')::text as columns,
+			pg_get_constraintdef(con.oid) as definition
+		FROM pg_constraint con
+		JOIN pg_class c ON con.conrelid = c.oid
+		JOIN pg_namespace n ON c.relnamespace = n.oid
+		WHERE n.nspname = $1
+			AND con.contype = 'c'
+		ORDER BY c.relname, con.conname`
+
+	rows, err := db.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var constraints []CheckConstraint
+	for rows.Next() {
+		var cc CheckConstraint
+		var columns string
+		if err := rows.Scan(&cc.TableName, &cc.ConstraintName, &columns, &cc.Definition); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(columns), &cc.Columns); err != nil {
+			return nil, fmt.Errorf("error decoding columns of constraint %s: %w", cc.ConstraintName, err)
+		}
+		constraints = append(constraints, cc)
+	}
+
+	return constraints, rows.Err()
+}
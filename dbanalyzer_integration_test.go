@@ -0,0 +1,59 @@
+//go:build integration
+
+package chatabase_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/midedickson/chatabase"
+)
+
+func TestChartSourcesIncludeViews(t *testing.T) {
+	tests := []struct {
+		opts chatabase.ChartSourceOptions
+		want []string
+	}{
+		{chatabase.ChartSourceOptions{}, []string{"orders"}},
+		{chatabase.ChartSourceOptions{IncludeViews: true}, []string{"orders", "paid_orders"}},
+		{chatabase.ChartSourceOptions{IncludeMaterializedViews: true}, []string{"orders", "region_totals"}},
+		{chatabase.ChartSourceOptions{IncludeViews: true, IncludeMaterializedViews: true}, []string{"orders", "paid_orders", "region_totals"}},
+	}
+
+	for _, tt := range tests {
+		got, err := chatabase.GetChartSources(db, "chatabase_it", tt.opts)
+		if err != nil {
+			t.Fatalf("GetChartSources(%+v) error = %v", tt.opts, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetChartSources(%+v) = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaColumnInfoOfViews(t *testing.T) {
+	tests := []struct {
+		relation string
+		want     []string // name and data type of each column
+	}{
+		{"paid_orders", []string{"id integer", "region text", "amount double precision"}},
+		{"region_totals", []string{"region text", "total double precision"}},
+	}
+
+	for _, tt := range tests {
+		columns, err := chatabase.GetSchemaColumnInfo(db, "chatabase_it", tt.relation)
+		if err != nil {
+			t.Fatalf("GetSchemaColumnInfo(%s) error = %v", tt.relation, err)
+		}
+		got := make([]string, len(columns))
+		for i, column := range columns {
+			got[i] = column.Name + " " + column.DataType
+			if column.Position != i+1 {
+				t.Errorf("%s.%s has position %d, want %d", tt.relation, column.Name, column.Position, i+1)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetSchemaColumnInfo(%s) = %v, want %v", tt.relation, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package chatabase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SQL dialects BuildChartQuery can generate. An empty ChartConfig.Dialect
+// is treated as DialectPostgres.
+const (
+	DialectPostgres   = "postgres"
+	DialectMySQL      = "mysql"
+	DialectSQLite     = "sqlite"
+	DialectClickHouse = "clickhouse"
+	DialectBigQuery   = "bigquery"
+)
+
+// ValidDialects are the accepted ChartConfig.Dialect values
+var ValidDialects = []string{DialectPostgres, DialectMySQL, DialectSQLite, DialectClickHouse, DialectBigQuery}
+
+// dialectOf returns the dialect a configuration is generated for
+func dialectOf(config *ChartConfig) string {
+	if config.Dialect == "" {
+		return DialectPostgres
+	}
+	return config.Dialect
+}
+
+// supportsDatabase reports whether a dialect can qualify tables with a
+// database (or, for Postgres, a schema; for BigQuery, a dataset) name
+func supportsDatabase(dialect string) bool {
+	return dialect != DialectSQLite
+}
+
+// placeholder returns the bind parameter marker for the nth argument
+func placeholder(dialect string, n int) string {
+	switch dialect {
+	case DialectMySQL, DialectSQLite, DialectClickHouse:
+		return "?"
+	case DialectBigQuery:
+		return fmt.Sprintf("@p%d", n)
+	default:
+		return fmt.Sprintf("$%d", n)
+	}
+}
+
+// quoteIdent renders s as a quoted identifier: backticks for MySQL and
+// BigQuery, double quotes otherwise. BigQuery escapes with backslashes.
+func quoteIdent(dialect, s string) string {
+	switch dialect {
+	case DialectMySQL:
+		return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+	case DialectBigQuery:
+		return "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(s) + "`"
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// argBinder numbers bind parameters for a dialect and collects their values
+// in placeholder order. sources records the config field each arg came from,
+// as set in source before binding. With named set, placeholders are :p1, :p2,
+// ... for sqlx named queries instead of the dialect's markers. With inSlices
+// set, placeholders are '?' and IN lists bind one slice for sqlx.In.
+type argBinder struct {
+	dialect  string
+	named    bool
+	inSlices bool
+	next     int
+	args     []interface{}
+	source   string
+	sources  []string
+}
+
+// newArgBinder returns a binder for the config's dialect
+func newArgBinder(config *ChartConfig) *argBinder {
+	return &argBinder{dialect: dialectOf(config), next: 1}
+}
+
+// placeholder returns the marker for the nth argument
+func (b *argBinder) placeholder(n int) string {
+	if b.named {
+		if b.dialect == DialectBigQuery {
+			return fmt.Sprintf("@p%d", n)
+		}
+		return fmt.Sprintf(":p%d", n)
+	}
+	if b.inSlices {
+		return "?"
+	}
+	return placeholder(b.dialect, n)
+}
+
+// namedArgs returns the args keyed by their named placeholder, p1, p2, ...
+func (b *argBinder) namedArgs() map[string]interface{} {
+	args := make(map[string]interface{}, len(b.args))
+	for i, arg := range b.args {
+		args[fmt.Sprintf("p%d", i+1)] = arg
+	}
+	return args
+}
+
+// bind records value as the next argument and returns its placeholder
+func (b *argBinder) bind(value interface{}) string {
+	marker := b.placeholder(b.next)
+	b.push(value)
+	b.next++
+	return marker
+}
+
+// push appends args with the current source
+func (b *argBinder) push(values ...interface{}) {
+	for _, value := range values {
+		b.args = append(b.args, value)
+		b.sources = append(b.sources, b.source)
+	}
+}
+
+// bindList binds the values of an IN list and returns their comma-separated
+// placeholders, or a single placeholder holding the slice for sqlx.In
+func (b *argBinder) bindList(values []interface{}) string {
+	if b.inSlices {
+		return b.bind(values)
+	}
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = b.bind(value)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// bindRaw rewrites the '?' markers in a raw predicate to the dialect's
+// placeholders and appends values in order
+func (b *argBinder) bindRaw(raw string, values []interface{}) string {
+	var out strings.Builder
+	for _, ch := range raw {
+		if ch == '?' {
+			out.WriteString(b.placeholder(b.next))
+			b.next++
+		} else {
+			out.WriteRune(ch)
+		}
+	}
+	b.push(values...)
+	return out.String()
+}
+
+// bindSubquery renumbers the placeholders of a subquery into the enclosing
+// query and appends its args. The subquery may use '?' markers or $1..$n
+// relative to its own args, but not both. Quoted literals are left alone.
+func (b *argBinder) bindSubquery(sql string, args []interface{}) (string, error) {
+	var out strings.Builder
+	base := b.next
+	questionMarks, highest := 0, 0
+
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		switch {
+		case ch == '\'':
+			// Copy the literal through its closing quote
+			end := strings.IndexByte(sql[i+1:], '\'')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated string literal in subquery")
+			}
+			out.WriteString(sql[i : i+end+2])
+			i += end + 1
+		case ch == '?':
+			out.WriteString(b.placeholder(base + questionMarks))
+			questionMarks++
+		case ch == '$' && i+1 < len(sql) && sql[i+1] >= '0' && sql[i+1] <= '9':
+			j := i + 1
+			for j < len(sql) && sql[j] >= '0' && sql[j] <= '9' {
+				j++
+			}
+			n, _ := strconv.Atoi(sql[i+1 : j])
+			if n < 1 || n > len(args) {
+				return "", fmt.Errorf("subquery placeholder $%d has no matching argument", n)
+			}
+			if n > highest {
+				highest = n
+			}
+			out.WriteString(b.placeholder(base + n - 1))
+			i = j - 1
+		default:
+			out.WriteByte(ch)
+		}
+	}
+
+	if questionMarks > 0 && highest > 0 {
+		return "", fmt.Errorf("subquery mixes '?' and $n placeholders")
+	}
+	if highest > 0 && b.placeholder(1) == b.placeholder(2) {
+		return "", fmt.Errorf("subquery $n placeholders require a dialect with numbered placeholders; use '?'")
+	}
+	if count := questionMarks + highest; count != len(args) {
+		return "", fmt.Errorf("subquery has %d placeholders but %d args", count, len(args))
+	}
+
+	b.push(args...)
+	b.next += len(args)
+	return out.String(), nil
+}
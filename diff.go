@@ -0,0 +1,153 @@
+package chatabase
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Equal reports whether two configurations are semantically identical.
+//
+// Slices (tables, joins, series, filters, group-by, order-by) are compared
+// in order, because their order determines the generated SQL and its
+// placeholder numbering. A nil slice equals an empty one. Filter values are
+// compared by type as well as value, so float64(1) and int(1) differ.
+func (c *ChartConfig) Equal(other *ChartConfig) bool {
+	return len(DiffChartConfig(c, other)) == 0
+}
+
+// DiffChartConfig lists the differences between two configurations as
+// human-readable lines such as `limit: 100 -> 500` or `filters[2]: added`.
+// Fields are named by their JSON keys. It follows the same rules as Equal.
+func DiffChartConfig(a, b *ChartConfig) []string {
+	var diffs []string
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil || b == nil:
+		return []string{fmt.Sprintf("config: %s -> %s", describeNil(a == nil), describeNil(b == nil))}
+	}
+	diffValues("", reflect.ValueOf(*a), reflect.ValueOf(*b), &diffs)
+	return diffs
+}
+
+func describeNil(isNil bool) string {
+	if isNil {
+		return "nil"
+	}
+	return "set"
+}
+
+// diffValues appends the differences between a and b, which share a type,
+// to diffs using path as the field prefix
+func diffValues(path string, a, b reflect.Value, diffs *[]string) {
+	switch a.Kind() {
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			diffValues(joinPath(path, fieldName(field)), a.Field(i), b.Field(i), diffs)
+		}
+
+	case reflect.Slice:
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				*diffs = append(*diffs, elemPath+": added")
+			case i >= b.Len():
+				*diffs = append(*diffs, elemPath+": removed")
+			default:
+				diffValues(elemPath, a.Index(i), b.Index(i), diffs)
+			}
+		}
+
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			av, bv := a.MapIndex(keys[name]), b.MapIndex(keys[name])
+			elemPath := fmt.Sprintf("%s[%s]", path, name)
+			switch {
+			case !av.IsValid():
+				*diffs = append(*diffs, elemPath+": added")
+			case !bv.IsValid():
+				*diffs = append(*diffs, elemPath+": removed")
+			default:
+				diffValues(elemPath, av, bv, diffs)
+			}
+		}
+
+	case reflect.Ptr:
+		switch {
+		case a.IsNil() && b.IsNil():
+		case a.IsNil():
+			*diffs = append(*diffs, path+": added")
+		case b.IsNil():
+			*diffs = append(*diffs, path+": removed")
+		default:
+			diffValues(path, a.Elem(), b.Elem(), diffs)
+		}
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() || a.Elem().Type() != b.Elem().Type() {
+			if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+				*diffs = append(*diffs, fmt.Sprintf("%s: %s -> %s", path, formatDiffValue(a), formatDiffValue(b)))
+			}
+			return
+		}
+		diffValues(path, a.Elem(), b.Elem(), diffs)
+
+	case reflect.Func:
+		// Functions cannot be compared; only report one being set or cleared
+		if a.IsNil() != b.IsNil() {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %s -> %s", path, describeNil(a.IsNil()), describeNil(b.IsNil())))
+		}
+
+	default:
+		if a.Interface() != b.Interface() {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %s -> %s", path, formatDiffValue(a), formatDiffValue(b)))
+		}
+	}
+}
+
+// fieldName returns the JSON key of a struct field, falling back to its Go name
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// formatDiffValue renders a value for a diff line, quoting strings
+func formatDiffValue(v reflect.Value) string {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "null"
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
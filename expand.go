@@ -0,0 +1,110 @@
+package chatabase
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// varPattern matches ${NAME} tokens
+var varPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandConfig replaces ${NAME} tokens in the title, description, schema,
+// table and join names, and string filter values (including IN lists,
+// tuples, raw values and series filters) using lookup, e.g. os.LookupEnv.
+// Tokens lookup does not know are left intact, or reported as an error when
+// strict is set.
+func ExpandConfig(config *ChartConfig, lookup func(string) (string, bool), strict bool) error {
+	expand := func(s string) (string, error) {
+		var missing string
+		out := varPattern.ReplaceAllStringFunc(s, func(token string) string {
+			name := varPattern.FindStringSubmatch(token)[1]
+			if value, ok := lookup(name); ok {
+				return value
+			}
+			if missing == "" {
+				missing = name
+			}
+			return token
+		})
+		if strict && missing != "" {
+			return "", fmt.Errorf("undefined variable ${%s}", missing)
+		}
+		return out, nil
+	}
+
+	expandValue := func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return expand(s)
+		}
+		return v, nil
+	}
+
+	expandValues := func(values []interface{}) error {
+		for i := range values {
+			v, err := expandValue(values[i])
+			if err != nil {
+				return err
+			}
+			values[i] = v
+		}
+		return nil
+	}
+
+	var err error
+	if config.Title, err = expand(config.Title); err != nil {
+		return fmt.Errorf("title: %w", err)
+	}
+	if config.Description, err = expand(config.Description); err != nil {
+		return fmt.Errorf("description: %w", err)
+	}
+	if config.Schema, err = expand(config.Schema); err != nil {
+		return fmt.Errorf("schema: %w", err)
+	}
+
+	for i := range config.Tables {
+		table := &config.Tables[i]
+		if table.Name, err = expand(table.Name); err != nil {
+			return fmt.Errorf("table at index %d: %w", i, err)
+		}
+		for j := range table.Joins {
+			if table.Joins[j].Table, err = expand(table.Joins[j].Table); err != nil {
+				return fmt.Errorf("join at table index %d, join index %d: %w", i, j, err)
+			}
+		}
+	}
+	for i := range config.Joins {
+		if config.Joins[i].Table, err = expand(config.Joins[i].Table); err != nil {
+			return fmt.Errorf("join at joins index %d: %w", i, err)
+		}
+	}
+
+	expandFilter := func(filter *FilterConfig) error {
+		var err error
+		if filter.Value, err = expandValue(filter.Value); err != nil {
+			return err
+		}
+		if err := expandValues(filter.Values); err != nil {
+			return err
+		}
+		for _, tuple := range filter.Tuples {
+			if err := expandValues(tuple); err != nil {
+				return err
+			}
+		}
+		return expandValues(filter.RawValues)
+	}
+	for i := range config.Filters {
+		if err := expandFilter(&config.Filters[i]); err != nil {
+			return fmt.Errorf("filter at index %d: %w", i, err)
+		}
+	}
+	for i := range config.YAxis {
+		for j := range config.YAxis[i].SeriesFilter {
+			if err := expandFilter(&config.YAxis[i].SeriesFilter[j]); err != nil {
+				return fmt.Errorf("y_axis at index %d, series_filter index %d: %w", i, j, err)
+			}
+		}
+	}
+
+	return nil
+}
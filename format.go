@@ -0,0 +1,193 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String renders the configuration as compact, SQL-like pseudocode for logs
+// and reviews, e.g.
+//
+//	bar "Revenue" FROM orders o LEFT JOIN users u WHERE status = 'paid' GROUP BY o.created_at -> x: o.created_at, y: SUM(amount) AS total
+//
+// It is not executable SQL; use BuildChartQuery for that. Join conditions
+// are omitted for brevity and raw filters are shown verbatim.
+func (c *ChartConfig) String() string {
+	if c == nil {
+		return "<nil>"
+	}
+
+	var b strings.Builder
+	b.WriteString(c.ChartType)
+	if c.Title != "" {
+		b.WriteString(fmt.Sprintf(" %q", c.Title))
+	}
+	if len(c.DistinctOn) > 0 {
+		b.WriteString(" DISTINCT ON (" + strings.Join(c.DistinctOn, ", ") + ")")
+	}
+
+	if len(c.Tables) > 0 {
+		b.WriteString(" FROM ")
+		for i, table := range c.Tables {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(describeTable(table.Database, table.Name, table.Alias))
+			joins := table.Joins
+			if i == 0 {
+				joins = tableJoins(c)
+			}
+			for _, join := range joins {
+				joinType := join.Type
+				if joinType == "" {
+					joinType = "INNER"
+				}
+				b.WriteString(fmt.Sprintf(" %s JOIN %s", joinType, describeTable(join.Database, join.Table, join.Alias)))
+			}
+		}
+	}
+
+	if len(c.Filters) > 0 {
+		filters := make([]string, len(c.Filters))
+		for i, filter := range c.Filters {
+			filters[i] = describeFilter(filter)
+		}
+		b.WriteString(" WHERE " + strings.Join(filters, " AND "))
+	}
+
+	switch c.GroupByMode {
+	case GroupByModeRollup, GroupByModeCube:
+		b.WriteString(fmt.Sprintf(" GROUP BY %s(%s)", strings.ToUpper(c.GroupByMode), strings.Join(c.GroupBy, ", ")))
+	case GroupByModeGroupingSets:
+		sets := make([]string, len(c.GroupingSets))
+		for i, set := range c.GroupingSets {
+			sets[i] = "(" + strings.Join(set, ", ") + ")"
+		}
+		b.WriteString(" GROUP BY GROUPING SETS (" + strings.Join(sets, ", ") + ")")
+	default:
+		if len(c.GroupBy) > 0 {
+			b.WriteString(" GROUP BY " + strings.Join(c.GroupBy, ", "))
+		}
+	}
+
+	if len(c.Having) > 0 {
+		having := make([]string, len(c.Having))
+		for i, filter := range c.Having {
+			having[i] = describeFilter(filter)
+		}
+		b.WriteString(" HAVING " + strings.Join(having, " AND "))
+	}
+
+	if len(c.OrderBy) > 0 {
+		orders := make([]string, len(c.OrderBy))
+		for i, order := range c.OrderBy {
+			orders[i] = strings.TrimSpace(order.orderExpr() + " " + order.Direction)
+		}
+		b.WriteString(" ORDER BY " + strings.Join(orders, ", "))
+	}
+
+	b.WriteString(limitClause(c))
+
+	b.WriteString(" -> x: " + describeAxis(c.XAxis))
+	if len(c.YAxis) > 0 {
+		series := make([]string, len(c.YAxis))
+		for i, axis := range c.YAxis {
+			series[i] = describeAxis(axis)
+		}
+		b.WriteString(", y: " + strings.Join(series, ", "))
+	}
+
+	return b.String()
+}
+
+func describeTable(database, name, alias string) string {
+	if database != "" {
+		name = database + "." + name
+	}
+	if alias != "" {
+		return name + " " + alias
+	}
+	return name
+}
+
+func describeAxis(axis AxisConfig) string {
+	series, err := seriesExpr(nil, nil, 0, axis)
+	if err != nil {
+		// The series filter cannot apply to this axis; describe it without
+		axis.SeriesFilter = nil
+		series, _ = seriesExpr(nil, nil, 0, axis)
+	}
+	expr := castExpr(axis.Cast, series)
+	if axis.NullAs != nil {
+		expr = fmt.Sprintf("COALESCE(%s, %s)", expr, describeValue(axis.NullAs))
+	}
+	if axis.Alias != "" {
+		expr += " AS " + axis.Alias
+	}
+	return expr
+}
+
+func describeFilter(filter FilterConfig) string {
+	if filter.Raw != "" {
+		return "(" + filter.Raw + ")"
+	}
+
+	if len(filter.Columns) > 0 {
+		tuples := make([]string, len(filter.Tuples))
+		for i, tuple := range filter.Tuples {
+			tuples[i] = describeValues(tuple)
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(filter.Columns, ", "), filter.Operator, strings.Join(tuples, ", "))
+	}
+
+	if filter.Subquery != "" {
+		if isExistsOperator(filter.Operator) {
+			return fmt.Sprintf("%s (%s)", filter.Operator, filter.Subquery)
+		}
+		return fmt.Sprintf("%s %s (%s)", columnExpr(filter.Column, filter.JSONPath), filter.Operator, filter.Subquery)
+	}
+
+	column := columnExpr(filter.Column, filter.JSONPath)
+	if filter.ValueExpr != "" {
+		return fmt.Sprintf("%s %s (%s)", column, filter.Operator, filter.ValueExpr)
+	}
+	if filter.Relative != nil {
+		direction := filter.Relative.Direction
+		if direction == "" {
+			direction = RelativeLast
+		}
+		return fmt.Sprintf("%s IN %s %d %s", column, direction, filter.Relative.Count, filter.Relative.Unit)
+	}
+	switch strings.ToUpper(filter.Operator) {
+	case "BETWEEN":
+		if len(filter.Values) == 2 {
+			return fmt.Sprintf("%s BETWEEN %s AND %s", column, describeValue(filter.Values[0]), describeValue(filter.Values[1]))
+		}
+	case "IS NULL", "IS NOT NULL":
+		return column + " " + filter.Operator
+	}
+
+	if len(filter.Values) > 0 {
+		return fmt.Sprintf("%s %s %s", column, filter.Operator, describeValues(filter.Values))
+	}
+	return fmt.Sprintf("%s %s %s", column, filter.Operator, describeValue(filter.Value))
+}
+
+func describeValues(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = describeValue(v)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func describeValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quoteLiteral(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
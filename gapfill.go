@@ -0,0 +1,236 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GapFill makes every time bucket of a datetime X axis appear in the result,
+// even when no rows fall into it. The chart query is LEFT JOINed onto a
+// generate_series of buckets, so it is only supported by the postgres dialect.
+type GapFill struct {
+	// Series bounds, e.g. "2024-01-01". When empty they are taken from a
+	// BETWEEN, >=/> or <=/< filter on the X axis column. An end taken from
+	// < is exclusive: the series stops at the bucket holding the instant
+	// before it, so < '2024-02-01' ends with the January 31 bucket.
+	Start interface{} `json:"start,omitempty" yaml:"start,omitempty" toml:"start,omitempty"`
+	End   interface{} `json:"end,omitempty" yaml:"end,omitempty" toml:"end,omitempty"`
+
+	// Step between buckets, e.g. "1 day"; defaults to one TimeInterval
+	Step string `json:"step,omitempty" yaml:"step,omitempty" toml:"step,omitempty"`
+}
+
+// Time intervals beyond the DATE_TRUNC units
+const (
+	TimeIntervalISOWeek       = "iso_week"       // Weeks starting on Monday, as numbered by ISO 8601
+	TimeIntervalFiscalQuarter = "fiscal_quarter" // Quarters of a year starting in options.fiscal_year_start_month
+)
+
+// ValidTimeIntervals are the units a gap-filled X axis can be bucketed by
+var ValidTimeIntervals = []string{"hour", "day", "week", "month", "quarter", "year", TimeIntervalISOWeek, TimeIntervalFiscalQuarter}
+
+// validateGapFill checks that a gap-filled chart can be built
+func validateGapFill(config *ChartConfig) error {
+	if dialectOf(config) != DialectPostgres {
+		return fmt.Errorf("gap filling is only supported by the postgres dialect")
+	}
+	if config.XAxis.DataType != DataTypeDatetime {
+		return fmt.Errorf("gap filling requires a datetime x_axis, got data_type '%s'", config.XAxis.DataType)
+	}
+	if config.XAxis.Aggregation != "" {
+		return fmt.Errorf("gap filling cannot be combined with an x_axis aggregation")
+	}
+	if !contains(ValidTimeIntervals, config.Options.TimeInterval) {
+		return fmt.Errorf("gap filling requires options.time_interval to be one of: %s",
+			strings.Join(ValidTimeIntervals, ", "))
+	}
+
+	start, end, _ := gapFillBounds(config)
+	if start == nil || end == nil {
+		return fmt.Errorf("gap filling requires a start and end, or a BETWEEN, >=/> or <=/< filter on '%s'", config.XAxis.Column)
+	}
+
+	return nil
+}
+
+// validateFiscalYear checks options.fiscal_year_start_month
+func validateFiscalYear(config *ChartConfig) error {
+	month := config.Options.FiscalYearStartMonth
+	if month < 1 || month > 12 {
+		return fmt.Errorf("invalid options.fiscal_year_start_month %d: must be between 1 and 12", month)
+	}
+	if config.Options.TimeInterval != TimeIntervalFiscalQuarter {
+		return fmt.Errorf("options.fiscal_year_start_month requires options.time_interval '%s'", TimeIntervalFiscalQuarter)
+	}
+	return nil
+}
+
+// validateTimezone checks that options.timezone is a plausible IANA zone name
+// and that there are time buckets to apply it to
+func validateTimezone(config *ChartConfig) error {
+	if !isTimezoneName(config.Options.Timezone) {
+		return fmt.Errorf("invalid options.timezone '%s': must be an IANA zone name such as America/New_York", config.Options.Timezone)
+	}
+	if config.Options.GapFill == nil {
+		return fmt.Errorf("options.timezone applies to time buckets and requires options.gap_fill")
+	}
+	return nil
+}
+
+// isTimezoneName reports whether s looks like an IANA zone name: "UTC",
+// "Europe/Berlin", "America/Argentina/Buenos_Aires" or "Etc/GMT+5". Whether the
+// zone exists is left to the database.
+func isTimezoneName(s string) bool {
+	if len(s) > 64 {
+		return false
+	}
+	for _, part := range strings.Split(s, "/") {
+		if part == "" {
+			return false
+		}
+		for i, r := range part {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			case i > 0 && (r >= '0' && r <= '9' || r == '_' || r == '-' || r == '+'):
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// gapFillBounds returns the series start and end, falling back to a filter
+// on the X axis column or x_value. exclusive reports an end taken from a <
+// filter, which the rows never reach.
+func gapFillBounds(config *ChartConfig) (start, end interface{}, exclusive bool) {
+	gapFill := config.Options.GapFill
+	start, end = gapFill.Start, gapFill.End
+
+	for _, filter := range config.Filters {
+		if (filter.Column != config.XAxis.Column && filter.Column != "x_value") || len(filter.JSONPath) > 0 {
+			continue
+		}
+		switch strings.ToUpper(filter.Operator) {
+		case "BETWEEN":
+			if len(filter.Values) == 2 {
+				if start == nil {
+					start = filter.Values[0]
+				}
+				if end == nil {
+					end = filter.Values[1]
+				}
+			}
+		case ">=", ">":
+			if start == nil {
+				start = filter.Value
+			}
+		case "<=", "<":
+			if end == nil {
+				end = filter.Value
+				exclusive = filter.Operator == "<"
+			}
+		}
+	}
+
+	return start, end, exclusive
+}
+
+// bucketExpr truncates the X axis column to its time bucket:
+// DATE_TRUNC('day', created_at), or with a timezone
+// DATE_TRUNC('day', created_at AT TIME ZONE 'America/New_York')
+func bucketExpr(config *ChartConfig) string {
+	return truncExpr(config, inTimezone(config, columnExpr(config.XAxis.Column, config.XAxis.JSONPath)))
+}
+
+// truncExpr truncates a timestamp expression to the start of its
+// options.time_interval bucket. A fiscal quarter is shifted back by the
+// months the fiscal year starts late, truncated to a calendar quarter and
+// shifted forward again, so with an April start May 15 falls in the quarter
+// starting April 1.
+func truncExpr(config *ChartConfig, expr string) string {
+	switch config.Options.TimeInterval {
+	case TimeIntervalISOWeek:
+		// Postgres weeks already start on Monday as ISO 8601 does
+		return fmt.Sprintf("DATE_TRUNC('week', %s)", expr)
+	case TimeIntervalFiscalQuarter:
+		offset := config.Options.FiscalYearStartMonth - 1
+		if offset <= 0 {
+			return fmt.Sprintf("DATE_TRUNC('quarter', %s)", expr)
+		}
+		shift := fmt.Sprintf("INTERVAL '%d months'", offset)
+		return fmt.Sprintf("DATE_TRUNC('quarter', %s - %s) + %s", expr, shift, shift)
+	default:
+		return fmt.Sprintf("DATE_TRUNC(%s, %s)", quoteLiteral(config.Options.TimeInterval), expr)
+	}
+}
+
+// defaultStep returns one options.time_interval as a Postgres interval
+func defaultStep(config *ChartConfig) string {
+	switch config.Options.TimeInterval {
+	case TimeIntervalISOWeek:
+		return "1 week"
+	case TimeIntervalFiscalQuarter:
+		return "3 months"
+	default:
+		return "1 " + config.Options.TimeInterval
+	}
+}
+
+// inTimezone converts a timestamptz expression to the local time of
+// options.timezone, so buckets start at local midnight
+func inTimezone(config *ChartConfig, expr string) string {
+	if config.Options.Timezone == "" {
+		return expr
+	}
+	return fmt.Sprintf("%s AT TIME ZONE %s", expr, quoteLiteral(config.Options.Timezone))
+}
+
+// buildGapFillQuery wraps the grouped chart query in a CTE and LEFT JOINs it
+// onto a generate_series of buckets. Y axes with NullAs also apply it to the
+// empty buckets. The result is ordered by bucket; LIMIT applies to buckets.
+func buildGapFillQuery(config *ChartConfig, b *argBinder, data string) (string, *argBinder, error) {
+	if err := validateGapFill(config); err != nil {
+		return "", nil, err
+	}
+
+	start, end, exclusive := gapFillBounds(config)
+	step := config.Options.GapFill.Step
+	if step == "" {
+		step = defaultStep(config)
+	}
+
+	var query strings.Builder
+	b.source = "options.gap_fill"
+	startExpr := fmt.Sprintf("CAST(%s AS timestamptz)", b.bind(start))
+	endExpr := fmt.Sprintf("CAST(%s AS timestamptz)", b.bind(end))
+	if exclusive {
+		// The last bucket is the one holding the instant before the end
+		endExpr = fmt.Sprintf("(%s - INTERVAL '1 microsecond')", endExpr)
+	}
+	query.WriteString(fmt.Sprintf("WITH chart_data AS (%s), ", data))
+	query.WriteString(fmt.Sprintf(
+		"chart_series AS (SELECT generate_series(%s, %s, CAST(%s AS interval)) as x_value) ",
+		truncExpr(config, inTimezone(config, startExpr)),
+		inTimezone(config, endExpr),
+		b.bind(step)))
+
+	query.WriteString("SELECT chart_series.x_value")
+	for i, yAxis := range config.YAxis {
+		alias := yAlias(i, yAxis)
+		b.source = fmt.Sprintf("y_axis[%d].null_as", i)
+		value := "chart_data." + alias
+		if yAxis.NullAs != nil {
+			value = fmt.Sprintf("COALESCE(%s, %s)", value, b.bind(yAxis.NullAs))
+		}
+		query.WriteString(fmt.Sprintf(", %s as %s", value, alias))
+	}
+	if config.IncludeTotalCount {
+		query.WriteString(", COUNT(*) OVER () as " + TotalCountColumn)
+	}
+	query.WriteString(" FROM chart_series LEFT JOIN chart_data ON chart_data.x_value = chart_series.x_value")
+	query.WriteString(" ORDER BY chart_series.x_value")
+	query.WriteString(limitClause(config))
+
+	return query.String(), b, nil
+}
@@ -0,0 +1,40 @@
+package chatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGapFillEndFromFilter(t *testing.T) {
+	tests := []struct {
+		operator string
+		want     string
+	}{
+		{operator: "<=", want: "DATE_TRUNC('day', CAST($4 AS timestamptz)), CAST($5 AS timestamptz), CAST($6 AS interval)"},
+		{operator: "<", want: "DATE_TRUNC('day', CAST($4 AS timestamptz)), (CAST($5 AS timestamptz) - INTERVAL '1 microsecond'), CAST($6 AS interval)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator, func(t *testing.T) {
+			config := testConfig(
+				FilterConfig{Column: "o.created_at", Operator: ">=", Value: "2024-01-01"},
+				FilterConfig{Column: "o.created_at", Operator: tt.operator, Value: "2024-02-01"},
+			)
+			config.XAxis = AxisConfig{Column: "o.created_at", DataType: DataTypeDatetime}
+			config.YAxis[0].NullAs = 0
+			config.GroupBy = []string{"o.created_at"}
+			config.Options = ChartOptions{TimeInterval: "day", GapFill: &GapFill{}}
+
+			query, args, err := BuildChartQuery(config)
+			if err != nil {
+				t.Fatalf("BuildChartQuery() error = %v", err)
+			}
+			if !strings.Contains(query, tt.want) {
+				t.Errorf("query %q does not contain %q", query, tt.want)
+			}
+			if args[4] != "2024-02-01" {
+				t.Errorf("series end arg = %v, want 2024-02-01", args[4])
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package chatabase_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/midedickson/chatabase"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// TestGolden pins the SQL the query builder emits. Each testdata/golden/NAME.json
+// holds a ChartConfig; BuildChartQuery must turn it into exactly the query in
+// NAME.sql and the args in NAME.args. After an intended builder change run
+//
+//	go test -run TestGolden -update
+//
+// and review the rewritten files with git diff before committing them. The
+// configs are fed to BuildChartQuery as written, without normalization, so
+// every builder branch can be pinned, including ones validation rejects.
+func TestGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "golden", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no golden inputs in testdata/golden")
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(input, ".json")
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			checkGolden(t, name)
+		})
+	}
+}
+
+// checkGolden renders NAME.json and compares it with, or writes, NAME.sql and NAME.args
+func checkGolden(t *testing.T, name string) {
+	data, err := os.ReadFile(name + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config chatabase.ChartConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	query, args, err := chatabase.BuildChartQuery(&config)
+	if err != nil {
+		// Errors are pinned too, so a branch that starts or stops failing shows up
+		query = "-- error: " + err.Error()
+	}
+	argsJSON, err := json.MarshalIndent(args, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode args: %v", err)
+	}
+
+	got := map[string][]byte{
+		".sql":  []byte(query + "\n"),
+		".args": append(argsJSON, '\n'),
+	}
+	for _, ext := range []string{".sql", ".args"} {
+		if *update {
+			if err := os.WriteFile(name+ext, got[ext], 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(name + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got[ext], want) {
+			t.Errorf("%s differs; rerun with -update and review the diff if the change is intended\n  got:  %s  want: %s", ext, got[ext], want)
+		}
+	}
+}
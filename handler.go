@@ -0,0 +1,130 @@
+package chatabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// HandlerOptions controls ChartHandlerWithOptions
+type HandlerOptions struct {
+	// Timeout, if positive, bounds each request's query on top of the
+	// request context; an expired query answers 504
+	Timeout time.Duration
+
+	// MaxBytes limits the request body; <= 0 uses DefaultMaxConfigBytes
+	MaxBytes int64
+
+	// Execute is passed to ExecuteChartContext, e.g. to set a tenant or a
+	// server-side statement timeout. It may be nil.
+	Execute *ExecuteOptions
+
+	// ErrorLog receives the database errors, which are not sent to the
+	// client; nil uses the log package's standard logger
+	ErrorLog *log.Logger
+
+	// TableAllowlist, if set, becomes every POSTed config's TableAllowlist:
+	// configs reading other tables, or using raw filters, subqueries or value
+	// expressions, are answered 400
+	TableAllowlist []string
+
+	// Validate, if set, is called on each config after it is validated and
+	// normalized, e.g. to run ValidateColumnAccess; an error is answered 400
+	Validate func(*ChartConfig) error
+}
+
+// ChartHandler returns an http.Handler that executes the ChartConfig JSON
+// POSTed to it and answers with the scanned rows as a JSON array. It is a
+// convenience for prototypes and demo endpoints; see ChartHandlerWithOptions.
+func ChartHandler(db *sqlx.DB) http.Handler {
+	return ChartHandlerWithOptions(db, HandlerOptions{})
+}
+
+// ChartHandlerWithOptions is ChartHandler with a query timeout, body limit,
+// execute options and access checks. Errors are answered as
+// {"error": "..."} with status 405 for methods other than POST, 413 for an
+// oversized body, 400 for an invalid or rejected config, 504 when the query
+// times out and 500 when it fails. The query is canceled when the client goes
+// away.
+//
+// Without TableAllowlist and Validate a client can chart any table the
+// database user can read, and column expressions and raw filters are SQL.
+// Set both before exposing the handler to untrusted clients.
+func ChartHandlerWithOptions(db *sqlx.DB, opts HandlerOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed; POST a chart config")
+			return
+		}
+
+		config, err := ReadChartConfigWithOptions(r.Body, ReadOptions{MaxBytes: opts.MaxBytes})
+		if errors.Is(err, ErrConfigTooLarge) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Validate up front, with the tenant the query will run with, so a
+		// bad config is a 400 rather than a query failure
+		if opts.Execute != nil && opts.Execute.Tenant != nil {
+			config.Tenant = opts.Execute.Tenant
+		}
+		if opts.TableAllowlist != nil {
+			config.TableAllowlist = opts.TableAllowlist
+		}
+		if err := ValidateAndNormalizeConfig(config); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if opts.Validate != nil {
+			if err := opts.Validate(config); err != nil {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		ctx := r.Context()
+		if opts.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+			defer cancel()
+		}
+
+		rows, err := ExecuteChartContext(ctx, db, config, opts.Execute)
+		if err != nil {
+			logger := opts.ErrorLog
+			if logger == nil {
+				logger = log.Default()
+			}
+			logger.Printf("chatabase: chart %q: %v", config.Title, err)
+
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && r.Context().Err() == nil {
+				writeJSONError(w, http.StatusGatewayTimeout, "chart query timed out")
+				return
+			}
+			writeJSONError(w, http.StatusInternalServerError, "failed to execute chart query")
+			return
+		}
+
+		if rows == nil {
+			rows = []ChartDataRow{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(rows)
+	})
+}
+
+// writeJSONError answers with status and {"error": message}
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
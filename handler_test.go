@@ -0,0 +1,74 @@
+package chatabase
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChartHandlerRejectsConfigs(t *testing.T) {
+	const orders = `{"chart_type": "bar", "title": "Orders",
+		"tables": [{"name": "orders", "alias": "o"}],
+		"x_axis": {"column": "o.region"},
+		"y_axis": [{"column": "o.amount", "aggregation": "SUM"}],
+		"group_by": ["o.region"]`
+
+	validated := 0
+	opts := HandlerOptions{
+		TableAllowlist: []string{"orders"},
+		Validate: func(config *ChartConfig) error {
+			validated++
+			return ValidateColumnAccess(config, map[string][]string{"orders": {"region", "amount"}})
+		},
+	}
+	// Every request is rejected before the query runs, so no database is needed
+	handler := ChartHandlerWithOptions(nil, opts)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		want   string
+	}{
+		{"GET", http.MethodGet, "", http.StatusMethodNotAllowed, "method not allowed"},
+		{"invalid JSON", http.MethodPost, "{", http.StatusBadRequest, ""},
+		{"table not allowed", http.MethodPost, strings.Replace(orders, `"name": "orders"`, `"name": "payroll"`, 1) + "}",
+			http.StatusBadRequest, "not in the table allowlist"},
+		{"raw filter", http.MethodPost, orders + `, "filters": [{"raw": "o.amount > 0"}]}`,
+			http.StatusBadRequest, "raw SQL"},
+		{"column not allowed", http.MethodPost, orders + `, "filters": [{"column": "o.email", "operator": "=", "value": "x"}]}`,
+			http.StatusBadRequest, "column 'o.email' is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tt.method, "/charts", strings.NewReader(tt.body)))
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.status, recorder.Body)
+			}
+			var body map[string]string
+			if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode error body: %v", err)
+			}
+			if !strings.Contains(body["error"], tt.want) {
+				t.Errorf("error = %q, want one containing %q", body["error"], tt.want)
+			}
+		})
+	}
+	if validated != 1 {
+		t.Errorf("Validate called %d times, want once, for the only config passing the allowlist", validated)
+	}
+
+	opts.Validate = func(*ChartConfig) error { return errors.New("charts are read-only today") }
+	recorder := httptest.NewRecorder()
+	ChartHandlerWithOptions(nil, opts).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/charts", strings.NewReader(orders+"}")))
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "charts are read-only today") {
+		t.Errorf("Validate error answered %d: %s", recorder.Code, recorder.Body)
+	}
+}
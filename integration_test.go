@@ -0,0 +1,314 @@
+//go:build integration
+
+// The integration suite runs a battery of chart configs end to end against a
+// real PostgreSQL: each config goes through ToSql and ExecuteChart, and its
+// rows are compared with the expected results. TestMain starts a throwaway
+// postgres:16 container with testcontainers-go, so Docker must be running:
+//
+//	go test -tags integration ./...
+//
+// Set CHATABASE_TEST_DATABASE_URL to use an existing database instead; the
+// suite creates and drops its own chatabase_it schema there.
+package chatabase_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/midedickson/chatabase"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// db is the database holding the fixture, opened by TestMain
+var db *sqlx.DB
+
+// fixture is the schema every case runs against. Row 2 has a NULL status and
+// row 4 a NULL paid flag, to exercise the NULL handling of the filters. The
+// views are for the introspection tests.
+const fixture = `
+CREATE SCHEMA chatabase_it;
+CREATE TABLE chatabase_it.orders (
+	id         integer PRIMARY KEY,
+	region     text NOT NULL,
+	status     text,
+	amount     double precision NOT NULL,
+	paid       boolean,
+	tags       text[] NOT NULL,
+	created_at date NOT NULL,
+	price      numeric(10,2) NOT NULL
+);
+INSERT INTO chatabase_it.orders VALUES
+	(1, 'north', 'paid',     100, true,  '{urgent}',      '2024-01-01', 100.25),
+	(2, 'north', NULL,        50, false, '{}',            '2024-01-01',  50.25),
+	(3, 'south', 'paid',     200, true,  '{urgent,gift}', '2024-01-02', 200.50),
+	(4, 'south', 'refunded',  30, NULL,  '{}',            '2024-01-02',  30.50),
+	(5, 'east',  'paid',      70, true,  '{gift}',        '2024-01-03',  70.75);
+CREATE VIEW chatabase_it.paid_orders AS
+	SELECT id, region, amount FROM chatabase_it.orders WHERE status = 'paid';
+CREATE MATERIALIZED VIEW chatabase_it.region_totals AS
+	SELECT region, SUM(amount) AS total FROM chatabase_it.orders GROUP BY region;
+`
+
+// testCase is one chart and the rows it must return, each rendered as
+// "x [y1 y2 ...]", followed by " of n" with the total count. Unordered
+// cases compare the rows as a set.
+type testCase struct {
+	name      string
+	build     func() (*chatabase.ChartConfig, error)
+	want      []string
+	unordered bool
+}
+
+// byRegion starts a chart over the fixture grouped and ordered by region
+func byRegion(title string) *chatabase.ChartBuilder {
+	return chatabase.NewChart("bar", title).
+		Schema("chatabase_it").
+		From("orders").
+		X("region").
+		GroupByCol("region").
+		OrderBy("x_value", "ASC")
+}
+
+var cases = []testCase{
+	{
+		name:  "SUM grouped by region",
+		build: byRegion("sum").Y("amount", chatabase.WithAgg("SUM")).Build,
+		want:  []string{"east [70]", "north [150]", "south [230]"},
+	},
+	{
+		name:  "COUNT(*) counts NULLs, COUNT(column) skips them",
+		build: byRegion("count").Y("", chatabase.WithAgg("COUNT")).Y("status", chatabase.WithAgg("COUNT")).Build,
+		want:  []string{"east [1 1]", "north [2 1]", "south [2 2]"},
+	},
+	{
+		name:  "= true becomes IS TRUE",
+		build: byRegion("is true").Y("", chatabase.WithAgg("COUNT")).Where("paid", "=", true).Build,
+		want:  []string{"east [1]", "north [1]", "south [1]"},
+	},
+	{
+		name:  "!= 'true' becomes IS NOT TRUE and keeps NULLs",
+		build: byRegion("is not true").Y("", chatabase.WithAgg("COUNT")).Where("paid", "!=", "true").Build,
+		want:  []string{"north [1]", "south [1]"},
+	},
+	{
+		name:  "strict != true leaves NULLs out",
+		build: byRegion("strict").NullHandling(chatabase.NullHandlingStrict).Y("", chatabase.WithAgg("COUNT")).Where("paid", "!=", true).Build,
+		want:  []string{"north [1]"},
+	},
+	{
+		name:  "IN with NULL also matches NULLs",
+		build: byRegion("in null").Y("", chatabase.WithAgg("COUNT")).Where("status", "IN", "refunded", nil).Build,
+		want:  []string{"north [1]", "south [1]"},
+	},
+	{
+		name:  "BETWEEN dates",
+		build: byRegion("between").Y("amount", chatabase.WithAgg("SUM")).Where("created_at", "BETWEEN", "2024-01-02", "2024-01-03").Build,
+		want:  []string{"east [70]", "south [230]"},
+	},
+	{
+		name:  "LIKE",
+		build: byRegion("like").Y("", chatabase.WithAgg("COUNT")).Where("region", "LIKE", "no%").Build,
+		want:  []string{"north [2]"},
+	},
+	{
+		name:  "array containment",
+		build: byRegion("contains").Y("", chatabase.WithAgg("COUNT")).Where("tags", "@>", "urgent").Build,
+		want:  []string{"north [1]", "south [1]"},
+	},
+	{
+		name:  "ANY",
+		build: byRegion("any").Y("", chatabase.WithAgg("COUNT")).Where("tags", "ANY", "gift").Build,
+		want:  []string{"east [1]", "south [1]"},
+	},
+	{
+		name:  "HAVING on a Y alias",
+		build: byRegion("having").Y("amount", chatabase.WithAgg("SUM"), chatabase.WithAlias("total")).Having("total", ">", 100).Build,
+		want:  []string{"north [150]", "south [230]"},
+	},
+	{
+		name:  "x_value in WHERE is replaced by the X expression",
+		build: byRegion("x_value").Y("", chatabase.WithAgg("COUNT")).Where("x_value", "=", "north").Build,
+		want:  []string{"north [2]"},
+	},
+	{
+		name: "SUM over only NULLs stays NULL, COUNT of them is 0",
+		build: byRegion("null sum").
+			Y("CASE WHEN status = 'refunded' THEN amount END", chatabase.WithAgg("SUM")).
+			Y("CASE WHEN status = 'refunded' THEN amount END", chatabase.WithAgg("COUNT")).Build,
+		want: []string{"east [<nil> 0]", "north [<nil> 0]", "south [30 1]"},
+	},
+	{
+		name: "ORDER BY an aggregate that is not selected",
+		build: chatabase.NewChart("bar", "top").Schema("chatabase_it").From("orders").X("region").Y("amount", chatabase.WithAgg("SUM")).
+			GroupByCol("region").OrderByAgg("COUNT", "", "DESC").OrderBy("x_value", "ASC").Build,
+		want: []string{"north [150]", "south [230]", "east [70]"},
+	},
+	{
+		name:  "MEDIAN",
+		build: byRegion("median").Y("amount", chatabase.WithAgg(chatabase.AggregationMedian)).Build,
+		want:  []string{"east [70]", "north [75]", "south [115]"},
+	},
+	{
+		name:  "NULL X values group together",
+		build: chatabase.NewChart("bar", "status").Schema("chatabase_it").From("orders").X("status").Y("amount", chatabase.WithAgg("SUM")).GroupByCol("status").OrderBy("x_value", "ASC").Build,
+		want:  []string{"paid [370]", "refunded [30]", "<nil> [50]"},
+	},
+	{
+		name: "tenant filter",
+		build: chatabase.NewChart("bar", "tenant").Schema("chatabase_it").From("orders").X("status").Y("amount", chatabase.WithAgg("SUM")).
+			Where("amount", ">", 0).GroupByCol("status").OrderBy("x_value", "ASC").Tenant("region", "north").Build,
+		want: []string{"paid [100]", "<nil> [50]"},
+	},
+	{
+		name:  "total_count counts every group despite the limit",
+		build: byRegion("total").IncludeTotalCount().Y("", chatabase.WithAgg("COUNT")).Limit(1).Build,
+		want:  []string{"east [1] of 3"},
+	},
+	{
+		name: "pivot by status",
+		build: func() (*chatabase.ChartConfig, error) {
+			config, err := byRegion("pivot").Y("amount", chatabase.WithAgg("SUM")).Build()
+			if err != nil {
+				return nil, err
+			}
+			return chatabase.PivotConfig(config, "status", []string{"paid", "refunded"})
+		},
+		want: []string{"east [70 <nil>]", "north [100 <nil>]", "south [200 30]"},
+	},
+	{
+		name: "series filter",
+		build: func() (*chatabase.ChartConfig, error) {
+			return byRegion("series filter").
+				Y("amount", chatabase.WithAgg("SUM")).
+				Y("amount", chatabase.WithAgg("SUM"), chatabase.WithSeriesFilter("status", "=", "refunded")).
+				Where("created_at", ">=", "2024-01-02").
+				Build()
+		},
+		want: []string{"east [70 <nil>]", "south [230 30]"},
+	},
+	{
+		name: "WITH TIES",
+		build: func() (*chatabase.ChartConfig, error) {
+			config, err := chatabase.NewChart("bar", "ties").Schema("chatabase_it").From("orders").X("region").
+				Y("", chatabase.WithAgg("COUNT")).GroupByCol("region").OrderBy("y_value_1", "DESC").Limit(1).Build()
+			if err == nil {
+				config.WithTies = true
+			}
+			return config, err
+		},
+		want:      []string{"north [2]", "south [2]"},
+		unordered: true,
+	},
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(runIntegration(m))
+}
+
+// runIntegration connects to the test database, starting a container unless
+// CHATABASE_TEST_DATABASE_URL is set, loads the fixture and runs the tests
+func runIntegration(m *testing.M) int {
+	ctx := context.Background()
+
+	url := os.Getenv("CHATABASE_TEST_DATABASE_URL")
+	if url == "" {
+		container, err := postgres.Run(ctx, "postgres:16-alpine",
+			postgres.WithDatabase("chatabase"),
+			postgres.WithUsername("postgres"),
+			postgres.WithPassword("postgres"),
+			postgres.BasicWaitStrategies(),
+		)
+		defer func() {
+			if err := testcontainers.TerminateContainer(container); err != nil {
+				log.Printf("failed to terminate postgres container: %v", err)
+			}
+		}()
+		if err != nil {
+			log.Printf("failed to start postgres container: %v", err)
+			return 1
+		}
+
+		url, err = container.ConnectionString(ctx, "sslmode=disable")
+		if err != nil {
+			log.Printf("failed to get connection string: %v", err)
+			return 1
+		}
+	}
+
+	var err error
+	db, err = sqlx.Connect("pgx", url)
+	if err != nil {
+		log.Printf("failed to connect: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	_, _ = db.Exec("DROP SCHEMA IF EXISTS chatabase_it CASCADE")
+	if _, err := db.Exec(fixture); err != nil {
+		log.Printf("failed to create fixture: %v", err)
+		return 1
+	}
+	defer db.Exec("DROP SCHEMA chatabase_it CASCADE")
+
+	return m.Run()
+}
+
+func TestCharts(t *testing.T) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := run(tc); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// run builds and executes one case
+func run(tc testCase) error {
+	config, err := tc.build()
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	query, args, err := chatabase.ToSql(config.Clone())
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := chatabase.ExecuteChart(db, config, nil)
+	if err != nil {
+		return fmt.Errorf("query rejected: %w\n     %s %v", err, query, args)
+	}
+
+	got := make([]string, len(rows))
+	for i, row := range rows {
+		values := row.YValues.(map[string]interface{})
+		ys := make([]interface{}, len(row.Series))
+		for j, series := range row.Series {
+			ys[j] = values[series]
+		}
+		got[i] = fmt.Sprintf("%v %v", row.XValue, ys)
+		if config.IncludeTotalCount {
+			got[i] += fmt.Sprintf(" of %d", row.TotalCount)
+		}
+	}
+
+	want := tc.want
+	if tc.unordered {
+		want = append([]string{}, want...)
+		sort.Strings(got)
+		sort.Strings(want)
+	}
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("got %q, want %q\n     %s %v", got, want, query, args)
+	}
+	return nil
+}
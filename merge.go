@@ -0,0 +1,84 @@
+package chatabase
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeOptions controls MergeChartConfigWithOptions
+type MergeOptions struct {
+	// AppendFilters adds the override's filters and having filters after the
+	// base's instead of replacing them, e.g. to narrow a shared dashboard
+	// chart by one more condition
+	AppendFilters bool
+}
+
+// MergeChartConfig returns a copy of base with the non-zero fields of
+// override applied, and validates the result. Neither input is modified.
+//
+// Structs (x_axis and options) merge field by field, maps
+// (options.series_colors) merge key by key, and every other set field,
+// including pointers such as options.gap_fill, replaces the base's. Slices
+// (tables, y_axis, group_by, filters, having, order_by, ...) replace the
+// base's when the override's is non-empty. Zero values keep the base's
+// value, so an override can neither clear a field nor set a boolean or
+// number back to false or 0.
+func MergeChartConfig(base, override *ChartConfig) (*ChartConfig, error) {
+	return MergeChartConfigWithOptions(base, override, MergeOptions{})
+}
+
+// MergeChartConfigWithOptions is MergeChartConfig with options choosing
+// whether filters are replaced or appended
+func MergeChartConfigWithOptions(base, override *ChartConfig, opts MergeOptions) (*ChartConfig, error) {
+	if base == nil {
+		return nil, fmt.Errorf("base chart config is nil")
+	}
+	merged := base.Clone()
+	if override != nil {
+		override = override.Clone()
+		if opts.AppendFilters {
+			override.Filters = append(merged.Filters, override.Filters...)
+			override.Having = append(merged.Having, override.Having...)
+		}
+		mergeValues(reflect.ValueOf(merged).Elem(), reflect.ValueOf(override).Elem())
+	}
+
+	if err := merged.Validate(); err != nil {
+		return nil, fmt.Errorf("merged chart config is invalid: %w", err)
+	}
+	return merged, nil
+}
+
+// mergeValues sets the non-zero parts of src on dst, which share a type
+func mergeValues(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).IsExported() {
+				mergeValues(dst.Field(i), src.Field(i))
+			}
+		}
+
+	case reflect.Map:
+		if src.Len() == 0 {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), iter.Value())
+		}
+
+	case reflect.Slice:
+		if src.Len() > 0 {
+			dst.Set(src)
+		}
+
+	default:
+		if !src.IsZero() {
+			dst.Set(src)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package chatabase
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentSchemaVersion is the ChartConfig shape this package reads and writes.
+// Configs without a schema_version are version 0.
+const CurrentSchemaVersion = 1
+
+// ConfigMigration upgrades a decoded JSON config in place from the version it
+// is registered under to the next one
+type ConfigMigration func(raw map[string]interface{}) error
+
+// configMigrations holds the migration from each older version to the next
+var configMigrations = map[int]ConfigMigration{
+	0: migrateV0ToV1,
+}
+
+// MigrateConfig upgrades a stored JSON config of any older schema version to
+// the current shape, then unmarshals and validates it
+func MigrateConfig(raw []byte) (*ChartConfig, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	version := 0
+	if v, ok := doc["schema_version"]; ok {
+		f, ok := v.(float64)
+		if !ok || f != float64(int(f)) {
+			return nil, fmt.Errorf("invalid schema_version: %v", v)
+		}
+		version = int(f)
+	}
+
+	if version > CurrentSchemaVersion {
+		return nil, fmt.Errorf("schema_version %d is newer than supported version %d", version, CurrentSchemaVersion)
+	}
+
+	for ; version < CurrentSchemaVersion; version++ {
+		migrate, ok := configMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration registered from schema_version %d", version)
+		}
+		if err := migrate(doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate from schema_version %d: %w", version, err)
+		}
+		doc["schema_version"] = version + 1
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal migrated config: %w", err)
+	}
+
+	return UnmarshalChartConfig(string(migrated))
+}
+
+// migrateV0ToV1 wraps a single y_axis object in an array and renames the
+// untagged raw filter fields to their JSON names
+func migrateV0ToV1(raw map[string]interface{}) error {
+	if yAxis, ok := raw["y_axis"].(map[string]interface{}); ok {
+		raw["y_axis"] = []interface{}{yAxis}
+	}
+
+	filters, _ := raw["filters"].([]interface{})
+	for _, f := range filters {
+		filter, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for oldKey, newKey := range map[string]string{"Raw": "raw", "RawValues": "raw_values"} {
+			if v, ok := filter[oldKey]; ok {
+				delete(filter, oldKey)
+				filter[newKey] = v
+			}
+		}
+	}
+
+	return nil
+}
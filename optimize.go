@@ -0,0 +1,166 @@
+package chatabase
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// OptimizeFilters removes duplicate filters from config's filters and having
+// and fails when two filters contradict each other, e.g. amount > 100 and
+// amount < 50, which would make the chart empty.
+//
+// It is conservative. Only filters identical in every field are duplicates,
+// and filters carrying SQL (raw, value_expr, subqueries) are always kept, as
+// they may not be deterministic. Contradictions are only detected between
+// comparisons (=, <, <=, >, >=, BETWEEN) of one column with numbers.
+func OptimizeFilters(config *ChartConfig) error {
+	config.Filters = dedupeFilters(config.Filters)
+	config.Having = dedupeFilters(config.Having)
+
+	if err := findContradiction(config.Filters); err != nil {
+		return err
+	}
+	if err := findContradiction(config.Having); err != nil {
+		return fmt.Errorf("having: %w", err)
+	}
+	return nil
+}
+
+// dedupeFilters returns filters without the exact repeats of earlier ones
+func dedupeFilters(filters []FilterConfig) []FilterConfig {
+	var kept []FilterConfig
+	for _, filter := range filters {
+		if isDuplicateFilter(kept, filter) {
+			continue
+		}
+		kept = append(kept, filter)
+	}
+	if filters != nil && kept == nil {
+		kept = []FilterConfig{}
+	}
+	return kept
+}
+
+func isDuplicateFilter(filters []FilterConfig, filter FilterConfig) bool {
+	if filter.Raw != "" || filter.ValueExpr != "" || filter.Subquery != "" {
+		return false
+	}
+	for _, other := range filters {
+		if reflect.DeepEqual(other, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// numericRange is the set of numbers a comparison filter accepts
+type numericRange struct {
+	low, high             float64
+	hasLow, hasHigh       bool
+	lowStrict, highStrict bool // the bound itself is excluded
+}
+
+// filterRange returns the numbers filter accepts, if it compares a column
+// with numbers
+func filterRange(filter FilterConfig) (numericRange, bool) {
+	if filter.Raw != "" || filter.ValueExpr != "" || filter.Subquery != "" || filter.Relative != nil || len(filter.Columns) > 0 {
+		return numericRange{}, false
+	}
+
+	var r numericRange
+	switch strings.ToUpper(filter.Operator) {
+	case "BETWEEN":
+		if len(filter.Values) != 2 {
+			return r, false
+		}
+		low, okLow := filterNumber(filter.Values[0])
+		high, okHigh := filterNumber(filter.Values[1])
+		if !okLow || !okHigh {
+			return r, false
+		}
+		return numericRange{low: low, high: high, hasLow: true, hasHigh: true}, true
+	}
+
+	value, ok := filterNumber(filter.Value)
+	if !ok {
+		return r, false
+	}
+	switch strings.ToUpper(filter.Operator) {
+	case "=":
+		r = numericRange{low: value, high: value, hasLow: true, hasHigh: true}
+	case ">", ">=":
+		r = numericRange{low: value, hasLow: true, lowStrict: filter.Operator == ">"}
+	case "<", "<=":
+		r = numericRange{high: value, hasHigh: true, highStrict: filter.Operator == "<"}
+	default:
+		return r, false
+	}
+	return r, true
+}
+
+// empty reports whether no number is in the range
+func (r numericRange) empty() bool {
+	if !r.hasLow || !r.hasHigh {
+		return false
+	}
+	return r.low > r.high || (r.low == r.high && (r.lowStrict || r.highStrict))
+}
+
+// intersect returns the numbers in both ranges
+func (r numericRange) intersect(other numericRange) numericRange {
+	if other.hasLow && (!r.hasLow || other.low > r.low || (other.low == r.low && other.lowStrict)) {
+		r.low, r.hasLow, r.lowStrict = other.low, true, other.lowStrict
+	}
+	if other.hasHigh && (!r.hasHigh || other.high < r.high || (other.high == r.high && other.highStrict)) {
+		r.high, r.hasHigh, r.highStrict = other.high, true, other.highStrict
+	}
+	return r
+}
+
+// findContradiction returns an error naming the first filter that can never
+// be true, on its own or together with an earlier filter on the same column
+func findContradiction(filters []FilterConfig) error {
+	for i, filter := range filters {
+		r, ok := filterRange(filter)
+		if !ok {
+			continue
+		}
+		if r.empty() {
+			return fmt.Errorf("filter at index %d can never match: %s", i, describeFilter(filter))
+		}
+		for j := 0; j < i; j++ {
+			if !sameFilterColumn(filters[j], filter) {
+				continue
+			}
+			other, ok := filterRange(filters[j])
+			if ok && r.intersect(other).empty() {
+				return fmt.Errorf("filters at index %d and %d contradict each other: %s and %s",
+					j, i, describeFilter(filters[j]), describeFilter(filter))
+			}
+		}
+	}
+	return nil
+}
+
+func sameFilterColumn(a, b FilterConfig) bool {
+	return a.Column == b.Column && reflect.DeepEqual(a.JSONPath, b.JSONPath)
+}
+
+// filterNumber returns a filter value as a float64 if it is a Go number
+func filterNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
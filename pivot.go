@@ -0,0 +1,99 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildPivotQuery builds a crosstab of config: one row per X value, as
+// usual, and one column per pivot value instead of the single Y series,
+// holding the Y aggregate over the rows whose pivotColumn equals that value.
+// With month on the X axis, SUM(amount) on the Y axis and category as the
+// pivot column, the query selects
+//
+//	SUM(amount) FILTER (WHERE category = 'A') as "A", ...
+//
+// MySQL and SQLite have no FILTER clause and get SUM(CASE WHEN category =
+// 'A' THEN amount END) instead. The result columns are named after the pivot
+// values; scan them with ScanDynamicChart, or ScanChartWithConfig and the
+// config PivotConfig returns.
+//
+// config must have exactly one Y axis aggregated with SUM, COUNT, AVG, MIN
+// or MAX. Like BuildChartQuery, config is not otherwise validated.
+func BuildPivotQuery(config *ChartConfig, pivotColumn string, pivotValues []string) (string, []interface{}, error) {
+	pivoted, err := PivotConfig(config, pivotColumn, pivotValues)
+	if err != nil {
+		return "", nil, err
+	}
+	return BuildChartQuery(pivoted)
+}
+
+// PivotConfig returns a copy of config with its Y axis replaced by one
+// filtered series per pivot value, as BuildPivotQuery builds it
+func PivotConfig(config *ChartConfig, pivotColumn string, pivotValues []string) (*ChartConfig, error) {
+	if !isColumnName(pivotColumn) {
+		return nil, fmt.Errorf("pivot column '%s' must be a column name", pivotColumn)
+	}
+	if len(pivotValues) == 0 {
+		return nil, fmt.Errorf("pivot needs at least one pivot value")
+	}
+	seen := make(map[string]bool, len(pivotValues))
+	for _, value := range pivotValues {
+		if seen[value] {
+			return nil, fmt.Errorf("pivot value '%s' is listed twice", value)
+		}
+		seen[value] = true
+	}
+	if len(config.YAxis) != 1 {
+		return nil, fmt.Errorf("pivot needs exactly one y_axis, got %d", len(config.YAxis))
+	}
+	cell := config.YAxis[0]
+	if cell.Numerator != nil || cell.Denominator != nil {
+		return nil, fmt.Errorf("pivot cannot use a derived y_axis")
+	}
+	if len(cell.SeriesFilter) > 0 {
+		return nil, fmt.Errorf("pivot cannot use a y_axis series_filter; add the conditions to filters")
+	}
+	if !contains(orderAggregations, cell.Aggregation) {
+		return nil, fmt.Errorf("invalid pivot aggregation '%s'. Must be one of: %s", cell.Aggregation, strings.Join(orderAggregations, ", "))
+	}
+
+	pivoted := config.Clone()
+	dialect := dialectOf(config)
+	column := columnExpr(cell.Column, cell.JSONPath)
+	pivoted.YAxis = make([]AxisConfig, len(pivotValues))
+	for i, value := range pivotValues {
+		condition := fmt.Sprintf("%s = %s", pivotColumn, pivotLiteral(dialect, value))
+
+		series := cell
+		series.Label = value
+		series.Alias = quoteIdent(dialect, value)
+		series.Aggregation = ""
+		series.JSONPath = nil
+		if dialect == DialectPostgres {
+			series.Column = fmt.Sprintf("%s FILTER (WHERE %s)", aggregateExpr(nil, cell, column), condition)
+		} else {
+			value := column
+			if value == "" || value == "*" {
+				value = "1"
+			}
+			series.Column = aggregateExpr(nil, cell, fmt.Sprintf("CASE WHEN %s THEN %s END", condition, value))
+		}
+		pivoted.YAxis[i] = series
+	}
+	return pivoted, nil
+}
+
+// pivotLiteral quotes a pivot value as a string literal. MySQL and
+// ClickHouse also read backslash escapes in literals, so backslashes are
+// doubled there.
+func pivotLiteral(dialect, value string) string {
+	if dialect == DialectBigQuery {
+		// BigQuery has no '' escape, only backslash escapes
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+	}
+	if dialect == DialectMySQL || dialect == DialectClickHouse {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return quoteLiteral(value)
+}
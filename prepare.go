@@ -0,0 +1,110 @@
+package chatabase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ChartStmt is a prepared chart query that can be run repeatedly with
+// different filter values.
+//
+// The statement fixes the structure of the config it was prepared from: the
+// tables, axes, operators, the number of IN values and so on. Only the bound
+// values may change between runs. A config whose structure changes, e.g. a
+// filter added or an IN list grown, must be prepared again.
+type ChartStmt struct {
+	stmt   *sqlx.Stmt
+	query  string
+	config *ChartConfig // maps result columns to series
+
+	// Args are the values the statement was prepared with, in placeholder order
+	Args []interface{}
+
+	// ArgSources names the config field each placeholder was bound from,
+	// e.g. "filters[1]", "tenant" or "y_axis[0].null_as"
+	ArgSources []string
+}
+
+// PrepareChart validates config, builds its query and prepares it on db
+func PrepareChart(db *sqlx.DB, config *ChartConfig) (*ChartStmt, error) {
+	return PrepareChartContext(context.Background(), db, config)
+}
+
+// PrepareChartContext is PrepareChart with a context for cancellation and timeouts
+func PrepareChartContext(ctx context.Context, db *sqlx.DB, config *ChartConfig) (*ChartStmt, error) {
+	if err := ValidateAndNormalizeConfig(config); err != nil {
+		return nil, err
+	}
+
+	query, b, err := buildChartQuery(config, newArgBinder(config))
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare chart query: %w", err)
+	}
+
+	return &ChartStmt{stmt: stmt, query: query, config: config.Clone(), Args: b.args, ArgSources: b.sources}, nil
+}
+
+// ArgsFor builds the args for config, which must have the same structure as
+// the config the statement was prepared from
+func (s *ChartStmt) ArgsFor(config *ChartConfig) ([]interface{}, error) {
+	if err := ValidateAndNormalizeConfig(config); err != nil {
+		return nil, err
+	}
+
+	query, b, err := buildChartQuery(config, newArgBinder(config))
+	if err != nil {
+		return nil, err
+	}
+	if query != s.query {
+		return nil, fmt.Errorf("chart config structure differs from the prepared statement; prepare it again")
+	}
+
+	return b.args, nil
+}
+
+// Query runs the statement with args in placeholder order and scans the rows
+func (s *ChartStmt) Query(args ...interface{}) ([]ChartDataRow, error) {
+	return s.QueryContext(context.Background(), args...)
+}
+
+// QueryContext is Query with a context for cancellation and timeouts
+func (s *ChartStmt) QueryContext(ctx context.Context, args ...interface{}) ([]ChartDataRow, error) {
+	if len(args) != len(s.Args) {
+		return nil, fmt.Errorf("prepared chart query takes %d args, got %d", len(s.Args), len(args))
+	}
+
+	rows, err := s.stmt.QueryxContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute chart query: %w", err)
+	}
+	defer rows.Close()
+
+	return ScanChartWithConfig(rows, s.config)
+}
+
+// QueryConfig runs the statement with the values of config, which must have
+// the same structure as the prepared config
+func (s *ChartStmt) QueryConfig(ctx context.Context, config *ChartConfig) ([]ChartDataRow, error) {
+	args, err := s.ArgsFor(config)
+	if err != nil {
+		return nil, err
+	}
+	return s.QueryContext(ctx, args...)
+}
+
+// SQL returns the prepared query text
+func (s *ChartStmt) SQL() string {
+	return s.query
+}
+
+// Close releases the prepared statement
+func (s *ChartStmt) Close() error {
+	return s.stmt.Close()
+}
@@ -0,0 +1,509 @@
+package chatabase
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	pb "github.com/midedickson/chatabase/proto/chatabase/v1"
+)
+
+// ChartConfigFromProto converts a chatabase.v1.ChartConfig message to a
+// ChartConfig, then validates and normalizes it like a parsed config. Value
+// fields become nil, bool, int64, float64 or string.
+func ChartConfigFromProto(msg *pb.ChartConfig) (*ChartConfig, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("chart config message is nil")
+	}
+
+	config := chartConfigFromProto(msg)
+	if err := ValidateAndNormalizeConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ChartConfigToProto converts config to a chatabase.v1.ChartConfig message.
+// Tenant and TableAllowlist have no proto fields and are dropped. It fails for
+// an integer that overflows its int32 field and for a filter value other than
+// nil, a bool, an integer, a float, a string or a time.Time, which is sent as
+// an RFC 3339 string.
+func ChartConfigToProto(config *ChartConfig) (*pb.ChartConfig, error) {
+	if config == nil {
+		return nil, fmt.Errorf("chart config is nil")
+	}
+
+	msg, err := chartConfigToProto(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert chart config to protobuf: %w", err)
+	}
+	return msg, nil
+}
+
+// chartConfigFromProto converts msg without validating the result
+func chartConfigFromProto(msg *pb.ChartConfig) *ChartConfig {
+	config := &ChartConfig{
+		SchemaVersion:     int(msg.GetSchemaVersion()),
+		ChartType:         msg.GetChartType(),
+		Title:             msg.GetTitle(),
+		Description:       msg.GetDescription(),
+		Schema:            msg.GetSchema(),
+		Joins:             joinsFromProto(msg.GetJoins()),
+		Dialect:           msg.GetDialect(),
+		XAxis:             axisFromProto(msg.GetXAxis()),
+		GroupBy:           stringsFromProto(msg.GetGroupBy()),
+		GroupByMode:       msg.GetGroupByMode(),
+		Filters:           filtersFromProto(msg.GetFilters()),
+		Having:            filtersFromProto(msg.GetHaving()),
+		NullHandling:      msg.GetNullHandling(),
+		Options:           optionsFromProto(msg.GetOptions()),
+		DistinctOn:        stringsFromProto(msg.GetDistinctOn()),
+		Limit:             int(msg.GetLimit()),
+		WithTies:          msg.GetWithTies(),
+		IncludeTotalCount: msg.GetIncludeTotalCount(),
+	}
+
+	for _, table := range msg.GetTables() {
+		config.Tables = append(config.Tables, TableConfig{
+			Name:     table.GetName(),
+			Alias:    table.GetAlias(),
+			Database: table.GetDatabase(),
+			Joins:    joinsFromProto(table.GetJoins()),
+			Sample:   sampleFromProto(table.GetSample()),
+		})
+	}
+	for _, axis := range msg.GetYAxis() {
+		config.YAxis = append(config.YAxis, axisFromProto(axis))
+	}
+	for _, set := range msg.GetGroupingSets() {
+		config.GroupingSets = append(config.GroupingSets, stringsFromProto(set.GetValues()))
+	}
+	for _, order := range msg.GetOrderBy() {
+		config.OrderBy = append(config.OrderBy, OrderConfig{
+			Column:      order.GetColumn(),
+			Direction:   order.GetDirection(),
+			Aggregation: order.GetAggregation(),
+		})
+	}
+	return config
+}
+
+// chartConfigToProto converts config, naming the field that fails
+func chartConfigToProto(config *ChartConfig) (*pb.ChartConfig, error) {
+	msg := &pb.ChartConfig{
+		ChartType:         config.ChartType,
+		Title:             config.Title,
+		Description:       config.Description,
+		Schema:            config.Schema,
+		Joins:             joinsToProto(config.Joins),
+		Dialect:           config.Dialect,
+		GroupBy:           cloneSlice(config.GroupBy),
+		GroupByMode:       config.GroupByMode,
+		NullHandling:      config.NullHandling,
+		DistinctOn:        cloneSlice(config.DistinctOn),
+		WithTies:          config.WithTies,
+		IncludeTotalCount: config.IncludeTotalCount,
+	}
+
+	var err error
+	if msg.SchemaVersion, err = int32ToProto("schema_version", config.SchemaVersion); err != nil {
+		return nil, err
+	}
+	if msg.Limit, err = int32ToProto("limit", config.Limit); err != nil {
+		return nil, err
+	}
+
+	for _, table := range config.Tables {
+		t := &pb.TableConfig{
+			Name:     table.Name,
+			Alias:    table.Alias,
+			Database: table.Database,
+			Joins:    joinsToProto(table.Joins),
+		}
+		if table.Sample != nil {
+			t.Sample = &pb.TableSample{Method: table.Sample.Method, Percent: table.Sample.Percent}
+		}
+		msg.Tables = append(msg.Tables, t)
+	}
+
+	if msg.XAxis, err = axisToProto(config.XAxis); err != nil {
+		return nil, fmt.Errorf("x_axis: %w", err)
+	}
+	for i, axis := range config.YAxis {
+		y, err := axisToProto(axis)
+		if err != nil {
+			return nil, fmt.Errorf("y_axis at index %d: %w", i, err)
+		}
+		msg.YAxis = append(msg.YAxis, y)
+	}
+
+	for _, set := range config.GroupingSets {
+		msg.GroupingSets = append(msg.GroupingSets, &pb.StringList{Values: cloneSlice(set)})
+	}
+
+	if msg.Filters, err = filtersToProto("filters", config.Filters); err != nil {
+		return nil, err
+	}
+	if msg.Having, err = filtersToProto("having", config.Having); err != nil {
+		return nil, err
+	}
+
+	if msg.Options, err = optionsToProto(config.Options); err != nil {
+		return nil, fmt.Errorf("options: %w", err)
+	}
+
+	for _, order := range config.OrderBy {
+		msg.OrderBy = append(msg.OrderBy, &pb.OrderConfig{
+			Column:      order.Column,
+			Direction:   order.Direction,
+			Aggregation: order.Aggregation,
+		})
+	}
+	return msg, nil
+}
+
+// stringsFromProto copies a repeated string field, keeping an empty one nil
+func stringsFromProto(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return cloneSlice(values)
+}
+
+func joinsFromProto(msgs []*pb.JoinConfig) []JoinConfig {
+	var joins []JoinConfig
+	for _, join := range msgs {
+		joins = append(joins, JoinConfig{
+			Table:     join.GetTable(),
+			Alias:     join.GetAlias(),
+			Database:  join.GetDatabase(),
+			Type:      join.GetType(),
+			Condition: join.GetCondition(),
+		})
+	}
+	return joins
+}
+
+func joinsToProto(joins []JoinConfig) []*pb.JoinConfig {
+	var msgs []*pb.JoinConfig
+	for _, join := range joins {
+		msgs = append(msgs, &pb.JoinConfig{
+			Table:     join.Table,
+			Alias:     join.Alias,
+			Database:  join.Database,
+			Type:      join.Type,
+			Condition: join.Condition,
+		})
+	}
+	return msgs
+}
+
+func sampleFromProto(msg *pb.TableSample) *TableSample {
+	if msg == nil {
+		return nil
+	}
+	return &TableSample{Method: msg.GetMethod(), Percent: msg.GetPercent()}
+}
+
+func axisFromProto(msg *pb.AxisConfig) AxisConfig {
+	return AxisConfig{
+		Column:       msg.GetColumn(),
+		Label:        msg.GetLabel(),
+		Aggregation:  msg.GetAggregation(),
+		DataType:     msg.GetDataType(),
+		Format:       msg.GetFormat(),
+		Alias:        msg.GetAlias(),
+		Percentile:   msg.GetPercentile(),
+		Delimiter:    msg.GetDelimiter(),
+		JSONPath:     stringsFromProto(msg.GetJsonPath()),
+		NullAs:       valueFromProto(msg.GetNullAs()),
+		Cast:         msg.GetCast(),
+		AxisSide:     msg.GetAxisSide(),
+		Numerator:    operandFromProto(msg.GetNumerator()),
+		Denominator:  operandFromProto(msg.GetDenominator()),
+		Operation:    msg.GetOperation(),
+		SeriesFilter: filtersFromProto(msg.GetSeriesFilter()),
+	}
+}
+
+func axisToProto(axis AxisConfig) (*pb.AxisConfig, error) {
+	msg := &pb.AxisConfig{
+		Column:      axis.Column,
+		Label:       axis.Label,
+		Aggregation: axis.Aggregation,
+		DataType:    axis.DataType,
+		Format:      axis.Format,
+		Alias:       axis.Alias,
+		Percentile:  axis.Percentile,
+		Delimiter:   axis.Delimiter,
+		JsonPath:    cloneSlice(axis.JSONPath),
+		Cast:        axis.Cast,
+		AxisSide:    axis.AxisSide,
+		Numerator:   operandToProto(axis.Numerator),
+		Denominator: operandToProto(axis.Denominator),
+		Operation:   axis.Operation,
+	}
+
+	var err error
+	if msg.NullAs, err = optionalValueToProto(axis.NullAs); err != nil {
+		return nil, fmt.Errorf("null_as: %w", err)
+	}
+	if msg.SeriesFilter, err = filtersToProto("series_filter", axis.SeriesFilter); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func operandFromProto(msg *pb.Operand) *Operand {
+	if msg == nil {
+		return nil
+	}
+	return &Operand{Column: msg.GetColumn(), Aggregation: msg.GetAggregation()}
+}
+
+func operandToProto(operand *Operand) *pb.Operand {
+	if operand == nil {
+		return nil
+	}
+	return &pb.Operand{Column: operand.Column, Aggregation: operand.Aggregation}
+}
+
+func filtersFromProto(msgs []*pb.FilterConfig) []FilterConfig {
+	var filters []FilterConfig
+	for _, msg := range msgs {
+		filter := FilterConfig{
+			Column:       msg.GetColumn(),
+			Operator:     msg.GetOperator(),
+			Value:        valueFromProto(msg.GetValue()),
+			Values:       valuesFromProto(msg.GetValues()),
+			Columns:      stringsFromProto(msg.GetColumns()),
+			JSONPath:     stringsFromProto(msg.GetJsonPath()),
+			Subquery:     msg.GetSubquery(),
+			SubqueryArgs: valuesFromProto(msg.GetSubqueryArgs()),
+			ValueExpr:    msg.GetValueExpr(),
+			Raw:          msg.GetRaw(),
+			RawValues:    valuesFromProto(msg.GetRawValues()),
+		}
+		for _, tuple := range msg.GetTuples() {
+			filter.Tuples = append(filter.Tuples, valuesFromProto(tuple.GetValues()))
+		}
+		if relative := msg.GetRelative(); relative != nil {
+			filter.Relative = &RelativeDate{
+				Count:     int(relative.GetCount()),
+				Unit:      relative.GetUnit(),
+				Direction: relative.GetDirection(),
+			}
+		}
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
+// filtersToProto converts the filters of the field called name
+func filtersToProto(name string, filters []FilterConfig) ([]*pb.FilterConfig, error) {
+	var msgs []*pb.FilterConfig
+	for i, filter := range filters {
+		msg, err := filterToProto(filter)
+		if err != nil {
+			return nil, fmt.Errorf("%s at index %d: %w", name, i, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func filterToProto(filter FilterConfig) (*pb.FilterConfig, error) {
+	msg := &pb.FilterConfig{
+		Column:    filter.Column,
+		Operator:  filter.Operator,
+		Columns:   cloneSlice(filter.Columns),
+		JsonPath:  cloneSlice(filter.JSONPath),
+		Subquery:  filter.Subquery,
+		ValueExpr: filter.ValueExpr,
+		Raw:       filter.Raw,
+	}
+
+	var err error
+	if msg.Value, err = optionalValueToProto(filter.Value); err != nil {
+		return nil, fmt.Errorf("value: %w", err)
+	}
+	if msg.Values, err = valuesToProto(filter.Values); err != nil {
+		return nil, fmt.Errorf("values: %w", err)
+	}
+	for i, tuple := range filter.Tuples {
+		values, err := valuesToProto(tuple)
+		if err != nil {
+			return nil, fmt.Errorf("tuples at index %d: %w", i, err)
+		}
+		msg.Tuples = append(msg.Tuples, &pb.ValueList{Values: values})
+	}
+	if msg.SubqueryArgs, err = valuesToProto(filter.SubqueryArgs); err != nil {
+		return nil, fmt.Errorf("subquery_args: %w", err)
+	}
+	if msg.RawValues, err = valuesToProto(filter.RawValues); err != nil {
+		return nil, fmt.Errorf("raw_values: %w", err)
+	}
+	if filter.Relative != nil {
+		count, err := int32ToProto("relative.count", filter.Relative.Count)
+		if err != nil {
+			return nil, err
+		}
+		msg.Relative = &pb.RelativeDate{Count: count, Unit: filter.Relative.Unit, Direction: filter.Relative.Direction}
+	}
+	return msg, nil
+}
+
+func optionsFromProto(msg *pb.ChartOptions) ChartOptions {
+	options := ChartOptions{
+		Width:                int(msg.GetWidth()),
+		Height:               int(msg.GetHeight()),
+		Theme:                msg.GetTheme(),
+		Stacked:              msg.GetStacked(),
+		ShowLegend:           msg.GetShowLegend(),
+		ShowGrid:             msg.GetShowGrid(),
+		DateFormat:           msg.GetDateFormat(),
+		TimeInterval:         msg.GetTimeInterval(),
+		Timezone:             msg.GetTimezone(),
+		FiscalYearStartMonth: int(msg.GetFiscalYearStartMonth()),
+		Colors:               stringsFromProto(msg.GetColors()),
+		AnnotateSQL:          msg.GetAnnotateSql(),
+	}
+	if gapFill := msg.GetGapFill(); gapFill != nil {
+		options.GapFill = &GapFill{
+			Start: valueFromProto(gapFill.GetStart()),
+			End:   valueFromProto(gapFill.GetEnd()),
+			Step:  gapFill.GetStep(),
+		}
+	}
+	if colors := msg.GetSeriesColors(); len(colors) > 0 {
+		options.SeriesColors = make(map[string]string, len(colors))
+		for series, color := range colors {
+			options.SeriesColors[series] = color
+		}
+	}
+	return options
+}
+
+func optionsToProto(options ChartOptions) (*pb.ChartOptions, error) {
+	msg := &pb.ChartOptions{
+		Theme:        options.Theme,
+		Stacked:      options.Stacked,
+		ShowLegend:   options.ShowLegend,
+		ShowGrid:     options.ShowGrid,
+		DateFormat:   options.DateFormat,
+		TimeInterval: options.TimeInterval,
+		Timezone:     options.Timezone,
+		Colors:       cloneSlice(options.Colors),
+		AnnotateSql:  options.AnnotateSQL,
+	}
+
+	var err error
+	if msg.Width, err = int32ToProto("width", options.Width); err != nil {
+		return nil, err
+	}
+	if msg.Height, err = int32ToProto("height", options.Height); err != nil {
+		return nil, err
+	}
+	if msg.FiscalYearStartMonth, err = int32ToProto("fiscal_year_start_month", options.FiscalYearStartMonth); err != nil {
+		return nil, err
+	}
+
+	if options.GapFill != nil {
+		msg.GapFill = &pb.GapFill{Step: options.GapFill.Step}
+		if msg.GapFill.Start, err = optionalValueToProto(options.GapFill.Start); err != nil {
+			return nil, fmt.Errorf("gap_fill.start: %w", err)
+		}
+		if msg.GapFill.End, err = optionalValueToProto(options.GapFill.End); err != nil {
+			return nil, fmt.Errorf("gap_fill.end: %w", err)
+		}
+	}
+	if options.SeriesColors != nil {
+		msg.SeriesColors = make(map[string]string, len(options.SeriesColors))
+		for series, color := range options.SeriesColors {
+			msg.SeriesColors[series] = color
+		}
+	}
+	return msg, nil
+}
+
+// int32ToProto checks that n, the value of the field called name, fits its
+// int32 proto field
+func int32ToProto(name string, n int) (int32, error) {
+	if n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("%s: %d overflows int32", name, n)
+	}
+	return int32(n), nil
+}
+
+// valueFromProto returns the Go value of a chatabase.v1.Value message; an
+// unset message or kind, and null_value, are nil
+func valueFromProto(msg *pb.Value) interface{} {
+	switch kind := msg.GetKind().(type) {
+	case *pb.Value_BoolValue:
+		return kind.BoolValue
+	case *pb.Value_IntValue:
+		return kind.IntValue
+	case *pb.Value_DoubleValue:
+		return kind.DoubleValue
+	case *pb.Value_StringValue:
+		return kind.StringValue
+	default:
+		return nil
+	}
+}
+
+func valuesFromProto(msgs []*pb.Value) []interface{} {
+	var values []interface{}
+	for _, msg := range msgs {
+		values = append(values, valueFromProto(msg))
+	}
+	return values
+}
+
+func valuesToProto(values []interface{}) ([]*pb.Value, error) {
+	var msgs []*pb.Value
+	for i, value := range values {
+		msg, err := valueToProto(value)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// optionalValueToProto leaves a nil singular value unset rather than sending
+// null_value
+func optionalValueToProto(value interface{}) (*pb.Value, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return valueToProto(value)
+}
+
+// valueToProto returns the chatabase.v1.Value message of value
+func valueToProto(value interface{}) (*pb.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return &pb.Value{Kind: &pb.Value_NullValue{NullValue: true}}, nil
+	case bool:
+		return &pb.Value{Kind: &pb.Value_BoolValue{BoolValue: v}}, nil
+	case int, int8, int16, int32, int64:
+		return &pb.Value{Kind: &pb.Value_IntValue{IntValue: reflect.ValueOf(v).Int()}}, nil
+	case uint, uint8, uint16, uint32, uint64:
+		n := reflect.ValueOf(v).Uint()
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%d overflows int64", n)
+		}
+		return &pb.Value{Kind: &pb.Value_IntValue{IntValue: int64(n)}}, nil
+	case float32, float64:
+		return &pb.Value{Kind: &pb.Value_DoubleValue{DoubleValue: reflect.ValueOf(v).Float()}}, nil
+	case string:
+		return &pb.Value{Kind: &pb.Value_StringValue{StringValue: v}}, nil
+	case time.Time:
+		return &pb.Value{Kind: &pb.Value_StringValue{StringValue: v.Format(time.RFC3339Nano)}}, nil
+	default:
+		return nil, fmt.Errorf("cannot convert a value of type %T to chatabase.v1.Value", value)
+	}
+}
@@ -0,0 +1,1876 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: proto/chatabase/v1/chart_config.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChartConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SchemaVersion     int32           `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	ChartType         string          `protobuf:"bytes,2,opt,name=chart_type,json=chartType,proto3" json:"chart_type,omitempty"` // "line", "bar", "pie", "scatter", "area", "histogram"
+	Title             string          `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description       string          `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Tables            []*TableConfig  `protobuf:"bytes,5,rep,name=tables,proto3" json:"tables,omitempty"`
+	Schema            string          `protobuf:"bytes,6,opt,name=schema,proto3" json:"schema,omitempty"`
+	Joins             []*JoinConfig   `protobuf:"bytes,7,rep,name=joins,proto3" json:"joins,omitempty"`
+	Dialect           string          `protobuf:"bytes,8,opt,name=dialect,proto3" json:"dialect,omitempty"` // "postgres" (default), "mysql", "sqlite", "clickhouse", "bigquery"
+	XAxis             *AxisConfig     `protobuf:"bytes,9,opt,name=x_axis,json=xAxis,proto3" json:"x_axis,omitempty"`
+	YAxis             []*AxisConfig   `protobuf:"bytes,10,rep,name=y_axis,json=yAxis,proto3" json:"y_axis,omitempty"`
+	GroupBy           []string        `protobuf:"bytes,11,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	GroupByMode       string          `protobuf:"bytes,12,opt,name=group_by_mode,json=groupByMode,proto3" json:"group_by_mode,omitempty"` // "plain", "rollup", "cube", "grouping_sets"
+	GroupingSets      []*StringList   `protobuf:"bytes,13,rep,name=grouping_sets,json=groupingSets,proto3" json:"grouping_sets,omitempty"`
+	Filters           []*FilterConfig `protobuf:"bytes,14,rep,name=filters,proto3" json:"filters,omitempty"`
+	Having            []*FilterConfig `protobuf:"bytes,15,rep,name=having,proto3" json:"having,omitempty"`
+	NullHandling      string          `protobuf:"bytes,16,opt,name=null_handling,json=nullHandling,proto3" json:"null_handling,omitempty"` // "smart" (default), "strict"
+	Options           *ChartOptions   `protobuf:"bytes,17,opt,name=options,proto3" json:"options,omitempty"`
+	DistinctOn        []string        `protobuf:"bytes,18,rep,name=distinct_on,json=distinctOn,proto3" json:"distinct_on,omitempty"`
+	Limit             int32           `protobuf:"varint,19,opt,name=limit,proto3" json:"limit,omitempty"`
+	OrderBy           []*OrderConfig  `protobuf:"bytes,20,rep,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	WithTies          bool            `protobuf:"varint,21,opt,name=with_ties,json=withTies,proto3" json:"with_ties,omitempty"`
+	IncludeTotalCount bool            `protobuf:"varint,22,opt,name=include_total_count,json=includeTotalCount,proto3" json:"include_total_count,omitempty"`
+}
+
+func (x *ChartConfig) Reset() {
+	*x = ChartConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChartConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChartConfig) ProtoMessage() {}
+
+func (x *ChartConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChartConfig.ProtoReflect.Descriptor instead.
+func (*ChartConfig) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ChartConfig) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *ChartConfig) GetChartType() string {
+	if x != nil {
+		return x.ChartType
+	}
+	return ""
+}
+
+func (x *ChartConfig) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ChartConfig) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ChartConfig) GetTables() []*TableConfig {
+	if x != nil {
+		return x.Tables
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *ChartConfig) GetJoins() []*JoinConfig {
+	if x != nil {
+		return x.Joins
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetDialect() string {
+	if x != nil {
+		return x.Dialect
+	}
+	return ""
+}
+
+func (x *ChartConfig) GetXAxis() *AxisConfig {
+	if x != nil {
+		return x.XAxis
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetYAxis() []*AxisConfig {
+	if x != nil {
+		return x.YAxis
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetGroupBy() []string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetGroupByMode() string {
+	if x != nil {
+		return x.GroupByMode
+	}
+	return ""
+}
+
+func (x *ChartConfig) GetGroupingSets() []*StringList {
+	if x != nil {
+		return x.GroupingSets
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetFilters() []*FilterConfig {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetHaving() []*FilterConfig {
+	if x != nil {
+		return x.Having
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetNullHandling() string {
+	if x != nil {
+		return x.NullHandling
+	}
+	return ""
+}
+
+func (x *ChartConfig) GetOptions() *ChartOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetDistinctOn() []string {
+	if x != nil {
+		return x.DistinctOn
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ChartConfig) GetOrderBy() []*OrderConfig {
+	if x != nil {
+		return x.OrderBy
+	}
+	return nil
+}
+
+func (x *ChartConfig) GetWithTies() bool {
+	if x != nil {
+		return x.WithTies
+	}
+	return false
+}
+
+func (x *ChartConfig) GetIncludeTotalCount() bool {
+	if x != nil {
+		return x.IncludeTotalCount
+	}
+	return false
+}
+
+type TableConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Alias    string        `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	Database string        `protobuf:"bytes,3,opt,name=database,proto3" json:"database,omitempty"`
+	Joins    []*JoinConfig `protobuf:"bytes,4,rep,name=joins,proto3" json:"joins,omitempty"`
+	Sample   *TableSample  `protobuf:"bytes,5,opt,name=sample,proto3" json:"sample,omitempty"`
+}
+
+func (x *TableConfig) Reset() {
+	*x = TableConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TableConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableConfig) ProtoMessage() {}
+
+func (x *TableConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableConfig.ProtoReflect.Descriptor instead.
+func (*TableConfig) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TableConfig) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TableConfig) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *TableConfig) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *TableConfig) GetJoins() []*JoinConfig {
+	if x != nil {
+		return x.Joins
+	}
+	return nil
+}
+
+func (x *TableConfig) GetSample() *TableSample {
+	if x != nil {
+		return x.Sample
+	}
+	return nil
+}
+
+type TableSample struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method  string  `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"` // "SYSTEM", "BERNOULLI"
+	Percent float64 `protobuf:"fixed64,2,opt,name=percent,proto3" json:"percent,omitempty"`
+}
+
+func (x *TableSample) Reset() {
+	*x = TableSample{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TableSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableSample) ProtoMessage() {}
+
+func (x *TableSample) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableSample.ProtoReflect.Descriptor instead.
+func (*TableSample) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TableSample) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *TableSample) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+type JoinConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Table     string `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	Alias     string `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	Database  string `protobuf:"bytes,3,opt,name=database,proto3" json:"database,omitempty"`
+	Type      string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"` // "INNER", "LEFT", "RIGHT", "FULL"
+	Condition string `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"`
+}
+
+func (x *JoinConfig) Reset() {
+	*x = JoinConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JoinConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinConfig) ProtoMessage() {}
+
+func (x *JoinConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinConfig.ProtoReflect.Descriptor instead.
+func (*JoinConfig) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *JoinConfig) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *JoinConfig) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *JoinConfig) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *JoinConfig) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *JoinConfig) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+type AxisConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Column       string          `protobuf:"bytes,1,opt,name=column,proto3" json:"column,omitempty"`
+	Label        string          `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Aggregation  string          `protobuf:"bytes,3,opt,name=aggregation,proto3" json:"aggregation,omitempty"`
+	DataType     string          `protobuf:"bytes,4,opt,name=data_type,json=dataType,proto3" json:"data_type,omitempty"`
+	Format       string          `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`
+	Alias        string          `protobuf:"bytes,6,opt,name=alias,proto3" json:"alias,omitempty"`
+	Percentile   float64         `protobuf:"fixed64,7,opt,name=percentile,proto3" json:"percentile,omitempty"`
+	Delimiter    string          `protobuf:"bytes,8,opt,name=delimiter,proto3" json:"delimiter,omitempty"`
+	JsonPath     []string        `protobuf:"bytes,9,rep,name=json_path,json=jsonPath,proto3" json:"json_path,omitempty"`
+	NullAs       *Value          `protobuf:"bytes,10,opt,name=null_as,json=nullAs,proto3" json:"null_as,omitempty"`
+	Cast         string          `protobuf:"bytes,11,opt,name=cast,proto3" json:"cast,omitempty"`
+	AxisSide     string          `protobuf:"bytes,12,opt,name=axis_side,json=axisSide,proto3" json:"axis_side,omitempty"`
+	Numerator    *Operand        `protobuf:"bytes,13,opt,name=numerator,proto3" json:"numerator,omitempty"`
+	Denominator  *Operand        `protobuf:"bytes,14,opt,name=denominator,proto3" json:"denominator,omitempty"`
+	Operation    string          `protobuf:"bytes,15,opt,name=operation,proto3" json:"operation,omitempty"` // "/" (default), "*", "+", "-"
+	SeriesFilter []*FilterConfig `protobuf:"bytes,16,rep,name=series_filter,json=seriesFilter,proto3" json:"series_filter,omitempty"`
+}
+
+func (x *AxisConfig) Reset() {
+	*x = AxisConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AxisConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AxisConfig) ProtoMessage() {}
+
+func (x *AxisConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AxisConfig.ProtoReflect.Descriptor instead.
+func (*AxisConfig) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AxisConfig) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetDataType() string {
+	if x != nil {
+		return x.DataType
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetPercentile() float64 {
+	if x != nil {
+		return x.Percentile
+	}
+	return 0
+}
+
+func (x *AxisConfig) GetDelimiter() string {
+	if x != nil {
+		return x.Delimiter
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetJsonPath() []string {
+	if x != nil {
+		return x.JsonPath
+	}
+	return nil
+}
+
+func (x *AxisConfig) GetNullAs() *Value {
+	if x != nil {
+		return x.NullAs
+	}
+	return nil
+}
+
+func (x *AxisConfig) GetCast() string {
+	if x != nil {
+		return x.Cast
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetAxisSide() string {
+	if x != nil {
+		return x.AxisSide
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetNumerator() *Operand {
+	if x != nil {
+		return x.Numerator
+	}
+	return nil
+}
+
+func (x *AxisConfig) GetDenominator() *Operand {
+	if x != nil {
+		return x.Denominator
+	}
+	return nil
+}
+
+func (x *AxisConfig) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *AxisConfig) GetSeriesFilter() []*FilterConfig {
+	if x != nil {
+		return x.SeriesFilter
+	}
+	return nil
+}
+
+type Operand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Column      string `protobuf:"bytes,1,opt,name=column,proto3" json:"column,omitempty"`
+	Aggregation string `protobuf:"bytes,2,opt,name=aggregation,proto3" json:"aggregation,omitempty"`
+}
+
+func (x *Operand) Reset() {
+	*x = Operand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Operand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operand) ProtoMessage() {}
+
+func (x *Operand) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operand.ProtoReflect.Descriptor instead.
+func (*Operand) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Operand) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *Operand) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+type FilterConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Column       string        `protobuf:"bytes,1,opt,name=column,proto3" json:"column,omitempty"`
+	Operator     string        `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Value        *Value        `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Values       []*Value      `protobuf:"bytes,4,rep,name=values,proto3" json:"values,omitempty"`
+	Columns      []string      `protobuf:"bytes,5,rep,name=columns,proto3" json:"columns,omitempty"`
+	Tuples       []*ValueList  `protobuf:"bytes,6,rep,name=tuples,proto3" json:"tuples,omitempty"`
+	JsonPath     []string      `protobuf:"bytes,7,rep,name=json_path,json=jsonPath,proto3" json:"json_path,omitempty"`
+	Subquery     string        `protobuf:"bytes,8,opt,name=subquery,proto3" json:"subquery,omitempty"`
+	SubqueryArgs []*Value      `protobuf:"bytes,9,rep,name=subquery_args,json=subqueryArgs,proto3" json:"subquery_args,omitempty"`
+	ValueExpr    string        `protobuf:"bytes,10,opt,name=value_expr,json=valueExpr,proto3" json:"value_expr,omitempty"`
+	Relative     *RelativeDate `protobuf:"bytes,11,opt,name=relative,proto3" json:"relative,omitempty"`
+	Raw          string        `protobuf:"bytes,12,opt,name=raw,proto3" json:"raw,omitempty"`
+	RawValues    []*Value      `protobuf:"bytes,13,rep,name=raw_values,json=rawValues,proto3" json:"raw_values,omitempty"`
+}
+
+func (x *FilterConfig) Reset() {
+	*x = FilterConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilterConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterConfig) ProtoMessage() {}
+
+func (x *FilterConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterConfig.ProtoReflect.Descriptor instead.
+func (*FilterConfig) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FilterConfig) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *FilterConfig) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *FilterConfig) GetValue() *Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *FilterConfig) GetValues() []*Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *FilterConfig) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *FilterConfig) GetTuples() []*ValueList {
+	if x != nil {
+		return x.Tuples
+	}
+	return nil
+}
+
+func (x *FilterConfig) GetJsonPath() []string {
+	if x != nil {
+		return x.JsonPath
+	}
+	return nil
+}
+
+func (x *FilterConfig) GetSubquery() string {
+	if x != nil {
+		return x.Subquery
+	}
+	return ""
+}
+
+func (x *FilterConfig) GetSubqueryArgs() []*Value {
+	if x != nil {
+		return x.SubqueryArgs
+	}
+	return nil
+}
+
+func (x *FilterConfig) GetValueExpr() string {
+	if x != nil {
+		return x.ValueExpr
+	}
+	return ""
+}
+
+func (x *FilterConfig) GetRelative() *RelativeDate {
+	if x != nil {
+		return x.Relative
+	}
+	return nil
+}
+
+func (x *FilterConfig) GetRaw() string {
+	if x != nil {
+		return x.Raw
+	}
+	return ""
+}
+
+func (x *FilterConfig) GetRawValues() []*Value {
+	if x != nil {
+		return x.RawValues
+	}
+	return nil
+}
+
+type RelativeDate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count     int32  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Unit      string `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`           // "day", "week", "month"
+	Direction string `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"` // "last" (default), "next"
+}
+
+func (x *RelativeDate) Reset() {
+	*x = RelativeDate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RelativeDate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelativeDate) ProtoMessage() {}
+
+func (x *RelativeDate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelativeDate.ProtoReflect.Descriptor instead.
+func (*RelativeDate) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RelativeDate) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *RelativeDate) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *RelativeDate) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+type OrderConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Column      string `protobuf:"bytes,1,opt,name=column,proto3" json:"column,omitempty"`
+	Direction   string `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"` // "ASC", "DESC"
+	Aggregation string `protobuf:"bytes,3,opt,name=aggregation,proto3" json:"aggregation,omitempty"`
+}
+
+func (x *OrderConfig) Reset() {
+	*x = OrderConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderConfig) ProtoMessage() {}
+
+func (x *OrderConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderConfig.ProtoReflect.Descriptor instead.
+func (*OrderConfig) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OrderConfig) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *OrderConfig) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *OrderConfig) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+type ChartOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Width                int32             `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height               int32             `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Theme                string            `protobuf:"bytes,3,opt,name=theme,proto3" json:"theme,omitempty"`
+	Stacked              bool              `protobuf:"varint,4,opt,name=stacked,proto3" json:"stacked,omitempty"`
+	ShowLegend           bool              `protobuf:"varint,5,opt,name=show_legend,json=showLegend,proto3" json:"show_legend,omitempty"`
+	ShowGrid             bool              `protobuf:"varint,6,opt,name=show_grid,json=showGrid,proto3" json:"show_grid,omitempty"`
+	DateFormat           string            `protobuf:"bytes,7,opt,name=date_format,json=dateFormat,proto3" json:"date_format,omitempty"`
+	TimeInterval         string            `protobuf:"bytes,8,opt,name=time_interval,json=timeInterval,proto3" json:"time_interval,omitempty"`
+	GapFill              *GapFill          `protobuf:"bytes,9,opt,name=gap_fill,json=gapFill,proto3" json:"gap_fill,omitempty"`
+	Timezone             string            `protobuf:"bytes,10,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	FiscalYearStartMonth int32             `protobuf:"varint,11,opt,name=fiscal_year_start_month,json=fiscalYearStartMonth,proto3" json:"fiscal_year_start_month,omitempty"`
+	Colors               []string          `protobuf:"bytes,12,rep,name=colors,proto3" json:"colors,omitempty"`
+	SeriesColors         map[string]string `protobuf:"bytes,13,rep,name=series_colors,json=seriesColors,proto3" json:"series_colors,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	AnnotateSql          bool              `protobuf:"varint,14,opt,name=annotate_sql,json=annotateSql,proto3" json:"annotate_sql,omitempty"`
+}
+
+func (x *ChartOptions) Reset() {
+	*x = ChartOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChartOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChartOptions) ProtoMessage() {}
+
+func (x *ChartOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChartOptions.ProtoReflect.Descriptor instead.
+func (*ChartOptions) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ChartOptions) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *ChartOptions) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ChartOptions) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+func (x *ChartOptions) GetStacked() bool {
+	if x != nil {
+		return x.Stacked
+	}
+	return false
+}
+
+func (x *ChartOptions) GetShowLegend() bool {
+	if x != nil {
+		return x.ShowLegend
+	}
+	return false
+}
+
+func (x *ChartOptions) GetShowGrid() bool {
+	if x != nil {
+		return x.ShowGrid
+	}
+	return false
+}
+
+func (x *ChartOptions) GetDateFormat() string {
+	if x != nil {
+		return x.DateFormat
+	}
+	return ""
+}
+
+func (x *ChartOptions) GetTimeInterval() string {
+	if x != nil {
+		return x.TimeInterval
+	}
+	return ""
+}
+
+func (x *ChartOptions) GetGapFill() *GapFill {
+	if x != nil {
+		return x.GapFill
+	}
+	return nil
+}
+
+func (x *ChartOptions) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *ChartOptions) GetFiscalYearStartMonth() int32 {
+	if x != nil {
+		return x.FiscalYearStartMonth
+	}
+	return 0
+}
+
+func (x *ChartOptions) GetColors() []string {
+	if x != nil {
+		return x.Colors
+	}
+	return nil
+}
+
+func (x *ChartOptions) GetSeriesColors() map[string]string {
+	if x != nil {
+		return x.SeriesColors
+	}
+	return nil
+}
+
+func (x *ChartOptions) GetAnnotateSql() bool {
+	if x != nil {
+		return x.AnnotateSql
+	}
+	return false
+}
+
+type GapFill struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start *Value `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   *Value `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Step  string `protobuf:"bytes,3,opt,name=step,proto3" json:"step,omitempty"`
+}
+
+func (x *GapFill) Reset() {
+	*x = GapFill{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GapFill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GapFill) ProtoMessage() {}
+
+func (x *GapFill) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GapFill.ProtoReflect.Descriptor instead.
+func (*GapFill) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GapFill) GetStart() *Value {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *GapFill) GetEnd() *Value {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *GapFill) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+// Value is a filter value, the interface{} fields of the Go structs. An
+// unset kind, or null_value, is SQL NULL. Integers and doubles stay
+// distinct so an int64 is bound as an integer, as TOML configs bind them.
+type Value struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Kind:
+	//	*Value_NullValue
+	//	*Value_BoolValue
+	//	*Value_IntValue
+	//	*Value_DoubleValue
+	//	*Value_StringValue
+	Kind isValue_Kind `protobuf_oneof:"kind"`
+}
+
+func (x *Value) Reset() {
+	*x = Value{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value) ProtoMessage() {}
+
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{11}
+}
+
+func (m *Value) GetKind() isValue_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (x *Value) GetNullValue() bool {
+	if x, ok := x.GetKind().(*Value_NullValue); ok {
+		return x.NullValue
+	}
+	return false
+}
+
+func (x *Value) GetBoolValue() bool {
+	if x, ok := x.GetKind().(*Value_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+func (x *Value) GetIntValue() int64 {
+	if x, ok := x.GetKind().(*Value_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
+}
+
+func (x *Value) GetDoubleValue() float64 {
+	if x, ok := x.GetKind().(*Value_DoubleValue); ok {
+		return x.DoubleValue
+	}
+	return 0
+}
+
+func (x *Value) GetStringValue() string {
+	if x, ok := x.GetKind().(*Value_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+type isValue_Kind interface {
+	isValue_Kind()
+}
+
+type Value_NullValue struct {
+	NullValue bool `protobuf:"varint,1,opt,name=null_value,json=nullValue,proto3,oneof"`
+}
+
+type Value_BoolValue struct {
+	BoolValue bool `protobuf:"varint,2,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type Value_IntValue struct {
+	IntValue int64 `protobuf:"varint,3,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type Value_DoubleValue struct {
+	DoubleValue float64 `protobuf:"fixed64,4,opt,name=double_value,json=doubleValue,proto3,oneof"`
+}
+
+type Value_StringValue struct {
+	StringValue string `protobuf:"bytes,5,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+func (*Value_NullValue) isValue_Kind() {}
+
+func (*Value_BoolValue) isValue_Kind() {}
+
+func (*Value_IntValue) isValue_Kind() {}
+
+func (*Value_DoubleValue) isValue_Kind() {}
+
+func (*Value_StringValue) isValue_Kind() {}
+
+type ValueList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []*Value `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *ValueList) Reset() {
+	*x = ValueList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValueList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValueList) ProtoMessage() {}
+
+func (x *ValueList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValueList.ProtoReflect.Descriptor instead.
+func (*ValueList) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ValueList) GetValues() []*Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type StringList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *StringList) Reset() {
+	*x = StringList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StringList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringList) ProtoMessage() {}
+
+func (x *StringList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chatabase_v1_chart_config_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringList.ProtoReflect.Descriptor instead.
+func (*StringList) Descriptor() ([]byte, []int) {
+	return file_proto_chatabase_v1_chart_config_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StringList) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+var File_proto_chatabase_v1_chart_config_proto protoreflect.FileDescriptor
+
+var file_proto_chatabase_v1_chart_config_proto_rawDesc = []byte{
+	0x0a, 0x25, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
+	0x65, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x68, 0x61, 0x72, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x2e, 0x76, 0x31, 0x22, 0xff, 0x06, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x72, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x73,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a,
+	0x63, 0x68, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x63, 0x68, 0x61, 0x72, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x2e,
+	0x0a, 0x05, 0x6a, 0x6f, 0x69, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x69,
+	0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05, 0x6a, 0x6f, 0x69, 0x6e, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x78, 0x5f, 0x61, 0x78,
+	0x69, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x78, 0x69, 0x73, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x05, 0x78, 0x41, 0x78, 0x69, 0x73, 0x12, 0x2f, 0x0a, 0x06, 0x79, 0x5f, 0x61,
+	0x78, 0x69, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x78, 0x69, 0x73, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x52, 0x05, 0x79, 0x41, 0x78, 0x69, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x5f, 0x62, 0x79, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x42, 0x79, 0x12, 0x22, 0x0a, 0x0d, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x62,
+	0x79, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x42, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x3d, 0x0a, 0x0d, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x74, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x0c, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x74, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x68, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x32,
+	0x0a, 0x06, 0x68, 0x61, 0x76, 0x69, 0x6e, 0x67, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x68, 0x61, 0x76, 0x69,
+	0x6e, 0x67, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x75, 0x6c, 0x6c, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x6c,
+	0x69, 0x6e, 0x67, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6e, 0x75, 0x6c, 0x6c, 0x48,
+	0x61, 0x6e, 0x64, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x34, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x74, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x5f, 0x6f, 0x6e, 0x18, 0x12, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x4f, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x34, 0x0a, 0x08, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x62, 0x79,
+	0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x77, 0x69,
+	0x74, 0x68, 0x5f, 0x74, 0x69, 0x65, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x77,
+	0x69, 0x74, 0x68, 0x54, 0x69, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x6e, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x16,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xb6, 0x01, 0x0a, 0x0b, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x2e, 0x0a,
+	0x05, 0x6a, 0x6f, 0x69, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63,
+	0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x69, 0x6e,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05, 0x6a, 0x6f, 0x69, 0x6e, 0x73, 0x12, 0x31, 0x0a,
+	0x06, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x52, 0x06, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x22, 0x3f, 0x0a, 0x0b, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x22, 0x86, 0x01, 0x0a, 0x0a, 0x4a, 0x6f, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xae, 0x04, 0x0a, 0x0a, 0x41,
+	0x78, 0x69, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d,
+	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74,
+	0x61, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61,
+	0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69,
+	0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x69, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65,
+	0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x6a, 0x73, 0x6f, 0x6e, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x73, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x2c, 0x0a, 0x07, 0x6e, 0x75, 0x6c, 0x6c, 0x5f, 0x61, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x6e, 0x75, 0x6c, 0x6c, 0x41, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x61, 0x73, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x61, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x78, 0x69, 0x73, 0x5f, 0x73, 0x69, 0x64, 0x65, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x78, 0x69, 0x73, 0x53, 0x69, 0x64, 0x65, 0x12, 0x33,
+	0x0a, 0x09, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x6e, 0x64, 0x52, 0x09, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x12, 0x37, 0x0a, 0x0b, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x6e, 0x64, 0x52,
+	0x0b, 0x64, 0x65, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x0d, 0x73, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x10, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0c, 0x73,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x43, 0x0a, 0x07, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x20,
+	0x0a, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0xf5, 0x03, 0x0a, 0x0c, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x29, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x2b, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x12, 0x2f, 0x0a, 0x06, 0x74, 0x75, 0x70, 0x6c, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62,
+	0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x06, 0x74, 0x75, 0x70, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6a, 0x73, 0x6f, 0x6e,
+	0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x73, 0x6f,
+	0x6e, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x62, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x75, 0x62, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x12, 0x38, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x61, 0x72,
+	0x67, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0c, 0x73,
+	0x75, 0x62, 0x71, 0x75, 0x65, 0x72, 0x79, 0x41, 0x72, 0x67, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x45, 0x78, 0x70, 0x72, 0x12, 0x36, 0x0a, 0x08, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63,
+	0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x44, 0x61, 0x74, 0x65, 0x52, 0x08, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69,
+	0x76, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x72, 0x61, 0x77, 0x12, 0x32, 0x0a, 0x0a, 0x72, 0x61, 0x77, 0x5f, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x09, 0x72,
+	0x61, 0x77, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x56, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x44, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e,
+	0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x65, 0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xc4, 0x04, 0x0a, 0x0c, 0x43, 0x68, 0x61, 0x72,
+	0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x74, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x74, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x68, 0x6f, 0x77, 0x5f, 0x6c,
+	0x65, 0x67, 0x65, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x68, 0x6f,
+	0x77, 0x4c, 0x65, 0x67, 0x65, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x77, 0x5f,
+	0x67, 0x72, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x77,
+	0x47, 0x72, 0x69, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x6f, 0x72,
+	0x6d, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x65, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x69,
+	0x6d, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x30, 0x0a, 0x08, 0x67, 0x61,
+	0x70, 0x5f, 0x66, 0x69, 0x6c, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63,
+	0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x70, 0x46,
+	0x69, 0x6c, 0x6c, 0x52, 0x07, 0x67, 0x61, 0x70, 0x46, 0x69, 0x6c, 0x6c, 0x12, 0x1a, 0x0a, 0x08,
+	0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x35, 0x0a, 0x17, 0x66, 0x69, 0x73, 0x63,
+	0x61, 0x6c, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x6d, 0x6f,
+	0x6e, 0x74, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x66, 0x69, 0x73, 0x63, 0x61,
+	0x6c, 0x59, 0x65, 0x61, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x06, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x73, 0x12, 0x51, 0x0a, 0x0d, 0x73, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c,
+	0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68,
+	0x61, 0x72, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x43, 0x6f, 0x6c, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x73, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x6c, 0x6f, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6e,
+	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x73, 0x71, 0x6c, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x53, 0x71, 0x6c, 0x1a, 0x3f, 0x0a,
+	0x11, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x6c, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x6f,
+	0x0a, 0x07, 0x47, 0x61, 0x70, 0x46, 0x69, 0x6c, 0x6c, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x25, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73,
+	0x74, 0x65, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x22,
+	0xba, 0x01, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x6e, 0x75, 0x6c,
+	0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52,
+	0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x62, 0x6f,
+	0x6f, 0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00,
+	0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1d, 0x0a, 0x09, 0x69,
+	0x6e, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x08, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x64, 0x6f,
+	0x75, 0x62, 0x6c, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x00, 0x52, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12,
+	0x23, 0x0a, 0x0c, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x38, 0x0a, 0x09,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x06, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x68, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x24, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x42, 0x38, 0x5a, 0x36,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x69, 0x64, 0x65, 0x64,
+	0x69, 0x63, 0x6b, 0x73, 0x6f, 0x6e, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x2f, 0x76, 0x31, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_chatabase_v1_chart_config_proto_rawDescOnce sync.Once
+	file_proto_chatabase_v1_chart_config_proto_rawDescData = file_proto_chatabase_v1_chart_config_proto_rawDesc
+)
+
+func file_proto_chatabase_v1_chart_config_proto_rawDescGZIP() []byte {
+	file_proto_chatabase_v1_chart_config_proto_rawDescOnce.Do(func() {
+		file_proto_chatabase_v1_chart_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_chatabase_v1_chart_config_proto_rawDescData)
+	})
+	return file_proto_chatabase_v1_chart_config_proto_rawDescData
+}
+
+var file_proto_chatabase_v1_chart_config_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_chatabase_v1_chart_config_proto_goTypes = []interface{}{
+	(*ChartConfig)(nil),  // 0: chatabase.v1.ChartConfig
+	(*TableConfig)(nil),  // 1: chatabase.v1.TableConfig
+	(*TableSample)(nil),  // 2: chatabase.v1.TableSample
+	(*JoinConfig)(nil),   // 3: chatabase.v1.JoinConfig
+	(*AxisConfig)(nil),   // 4: chatabase.v1.AxisConfig
+	(*Operand)(nil),      // 5: chatabase.v1.Operand
+	(*FilterConfig)(nil), // 6: chatabase.v1.FilterConfig
+	(*RelativeDate)(nil), // 7: chatabase.v1.RelativeDate
+	(*OrderConfig)(nil),  // 8: chatabase.v1.OrderConfig
+	(*ChartOptions)(nil), // 9: chatabase.v1.ChartOptions
+	(*GapFill)(nil),      // 10: chatabase.v1.GapFill
+	(*Value)(nil),        // 11: chatabase.v1.Value
+	(*ValueList)(nil),    // 12: chatabase.v1.ValueList
+	(*StringList)(nil),   // 13: chatabase.v1.StringList
+	nil,                  // 14: chatabase.v1.ChartOptions.SeriesColorsEntry
+}
+var file_proto_chatabase_v1_chart_config_proto_depIdxs = []int32{
+	1,  // 0: chatabase.v1.ChartConfig.tables:type_name -> chatabase.v1.TableConfig
+	3,  // 1: chatabase.v1.ChartConfig.joins:type_name -> chatabase.v1.JoinConfig
+	4,  // 2: chatabase.v1.ChartConfig.x_axis:type_name -> chatabase.v1.AxisConfig
+	4,  // 3: chatabase.v1.ChartConfig.y_axis:type_name -> chatabase.v1.AxisConfig
+	13, // 4: chatabase.v1.ChartConfig.grouping_sets:type_name -> chatabase.v1.StringList
+	6,  // 5: chatabase.v1.ChartConfig.filters:type_name -> chatabase.v1.FilterConfig
+	6,  // 6: chatabase.v1.ChartConfig.having:type_name -> chatabase.v1.FilterConfig
+	9,  // 7: chatabase.v1.ChartConfig.options:type_name -> chatabase.v1.ChartOptions
+	8,  // 8: chatabase.v1.ChartConfig.order_by:type_name -> chatabase.v1.OrderConfig
+	3,  // 9: chatabase.v1.TableConfig.joins:type_name -> chatabase.v1.JoinConfig
+	2,  // 10: chatabase.v1.TableConfig.sample:type_name -> chatabase.v1.TableSample
+	11, // 11: chatabase.v1.AxisConfig.null_as:type_name -> chatabase.v1.Value
+	5,  // 12: chatabase.v1.AxisConfig.numerator:type_name -> chatabase.v1.Operand
+	5,  // 13: chatabase.v1.AxisConfig.denominator:type_name -> chatabase.v1.Operand
+	6,  // 14: chatabase.v1.AxisConfig.series_filter:type_name -> chatabase.v1.FilterConfig
+	11, // 15: chatabase.v1.FilterConfig.value:type_name -> chatabase.v1.Value
+	11, // 16: chatabase.v1.FilterConfig.values:type_name -> chatabase.v1.Value
+	12, // 17: chatabase.v1.FilterConfig.tuples:type_name -> chatabase.v1.ValueList
+	11, // 18: chatabase.v1.FilterConfig.subquery_args:type_name -> chatabase.v1.Value
+	7,  // 19: chatabase.v1.FilterConfig.relative:type_name -> chatabase.v1.RelativeDate
+	11, // 20: chatabase.v1.FilterConfig.raw_values:type_name -> chatabase.v1.Value
+	10, // 21: chatabase.v1.ChartOptions.gap_fill:type_name -> chatabase.v1.GapFill
+	14, // 22: chatabase.v1.ChartOptions.series_colors:type_name -> chatabase.v1.ChartOptions.SeriesColorsEntry
+	11, // 23: chatabase.v1.GapFill.start:type_name -> chatabase.v1.Value
+	11, // 24: chatabase.v1.GapFill.end:type_name -> chatabase.v1.Value
+	11, // 25: chatabase.v1.ValueList.values:type_name -> chatabase.v1.Value
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_proto_chatabase_v1_chart_config_proto_init() }
+func file_proto_chatabase_v1_chart_config_proto_init() {
+	if File_proto_chatabase_v1_chart_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChartConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TableConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TableSample); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JoinConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AxisConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Operand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilterConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelativeDate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChartOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GapFill); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Value); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValueList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_chatabase_v1_chart_config_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StringList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_proto_chatabase_v1_chart_config_proto_msgTypes[11].OneofWrappers = []interface{}{
+		(*Value_NullValue)(nil),
+		(*Value_BoolValue)(nil),
+		(*Value_IntValue)(nil),
+		(*Value_DoubleValue)(nil),
+		(*Value_StringValue)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_chatabase_v1_chart_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_chatabase_v1_chart_config_proto_goTypes,
+		DependencyIndexes: file_proto_chatabase_v1_chart_config_proto_depIdxs,
+		MessageInfos:      file_proto_chatabase_v1_chart_config_proto_msgTypes,
+	}.Build()
+	File_proto_chatabase_v1_chart_config_proto = out.File
+	file_proto_chatabase_v1_chart_config_proto_rawDesc = nil
+	file_proto_chatabase_v1_chart_config_proto_goTypes = nil
+	file_proto_chatabase_v1_chart_config_proto_depIdxs = nil
+}
@@ -0,0 +1,143 @@
+package chatabase
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/midedickson/chatabase/proto/chatabase/v1"
+)
+
+// fillAll sets every exported field reachable from v that has a JSON name to
+// a non-zero value: one-element slices and maps, and int64 for interface{}s,
+// which the proto Value round-trips as int64
+func fillAll(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "-" && field.IsExported() {
+				fillAll(v.Field(i))
+			}
+		}
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillAll(v.Elem())
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillAll(v.Index(0))
+	case reflect.Map:
+		key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fillAll(key)
+		fillAll(elem)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, elem)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(int64(7)))
+	case reflect.String:
+		v.SetString(v.Type().Name())
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(3)
+	case reflect.Float64:
+		v.SetFloat(2.5)
+	default:
+		panic(fmt.Sprintf("fillAll cannot fill %s", v.Type()))
+	}
+}
+
+func TestChartConfigProtoCoversEveryField(t *testing.T) {
+	var config ChartConfig
+	fillAll(reflect.ValueOf(&config).Elem())
+
+	msg, err := chartConfigToProto(&config)
+	if err != nil {
+		t.Fatalf("chartConfigToProto() error = %v", err)
+	}
+	// Through the wire format, as a gRPC service receives it
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received pb.ChartConfig
+	if err := proto.Unmarshal(data, &received); err != nil {
+		t.Fatal(err)
+	}
+
+	// fillAll's config is not a valid chart, so convert without validating
+	decoded := chartConfigFromProto(&received)
+	if !reflect.DeepEqual(decoded, &config) {
+		t.Errorf("round trip through protobuf lost fields:\ngot  %+v\nwant %+v", decoded, &config)
+	}
+}
+
+func TestChartConfigProtoValues(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := testConfig(
+		FilterConfig{Column: "o.status", Operator: "IN", Values: []interface{}{"paid", nil}},
+		FilterConfig{Column: "o.amount", Operator: "BETWEEN", Values: []interface{}{10, 2.5}},
+		FilterConfig{Column: "o.paid", Operator: "=", Value: true},
+		FilterConfig{Column: "o.created_at", Operator: ">=", Value: day},
+	)
+	config.Tenant = &TenantFilter{Column: "tenant_id", Value: 1}
+	config.TableAllowlist = []string{"orders"}
+
+	msg, err := ChartConfigToProto(config)
+	if err != nil {
+		t.Fatalf("ChartConfigToProto() error = %v", err)
+	}
+	decoded, err := ChartConfigFromProto(msg)
+	if err != nil {
+		t.Fatalf("ChartConfigFromProto() error = %v", err)
+	}
+
+	want := []FilterConfig{
+		{Column: "o.status", Operator: "IN", Values: []interface{}{"paid", nil}},
+		{Column: "o.amount", Operator: "BETWEEN", Values: []interface{}{int64(10), 2.5}},
+		{Column: "o.paid", Operator: "=", Value: true},
+		{Column: "o.created_at", Operator: ">=", Value: "2024-01-01T00:00:00Z"},
+	}
+	if !reflect.DeepEqual(decoded.Filters, want) {
+		t.Errorf("filters = %#v, want %#v", decoded.Filters, want)
+	}
+	if decoded.Tenant != nil || decoded.TableAllowlist != nil {
+		t.Errorf("tenant %v and allowlist %v came through protobuf", decoded.Tenant, decoded.TableAllowlist)
+	}
+
+	config.Filters = []FilterConfig{{Column: "o.id", Operator: "=", Value: []int{1}}}
+	if _, err := ChartConfigToProto(config); err == nil {
+		t.Error("ChartConfigToProto() accepted a slice filter value")
+	}
+	config.Limit = 1 << 40
+	config.Filters = nil
+	if _, err := ChartConfigToProto(config); err == nil {
+		t.Error("ChartConfigToProto() accepted a limit that overflows int32")
+	}
+}
+
+func TestChartConfigFromProtoValidates(t *testing.T) {
+	msg, err := ChartConfigToProto(testConfig())
+	if err != nil {
+		t.Fatalf("ChartConfigToProto() error = %v", err)
+	}
+	config, err := ChartConfigFromProto(msg)
+	if err != nil {
+		t.Fatalf("ChartConfigFromProto() error = %v", err)
+	}
+	if config.SchemaVersion != CurrentSchemaVersion || config.Options.Width != 800 {
+		t.Errorf("schema_version %d and width %d were not normalized", config.SchemaVersion, config.Options.Width)
+	}
+
+	msg.Title = ""
+	if _, err := ChartConfigFromProto(msg); err == nil || !strings.Contains(err.Error(), "title") {
+		t.Errorf("ChartConfigFromProto() error = %v, want a missing title error", err)
+	}
+	if _, err := ChartConfigFromProto(nil); err == nil {
+		t.Error("ChartConfigFromProto() accepted a nil message")
+	}
+}
@@ -1,5 +1,48 @@
 package chatabase
 
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// QueryHook inspects or rewrites a generated chart query before it runs.
+// Returning an error aborts execution.
+type QueryHook func(query string, args []interface{}) (string, []interface{}, error)
+
+// ExecuteOptions controls how ExecuteChart runs a chart query
+type ExecuteOptions struct {
+	// QueryHook is called after the query is built and before it is executed,
+	// e.g. to log the SQL or append row-level security predicates
+	QueryHook QueryHook
+
+	// Tenant, if set, replaces config.Tenant so callers can scope a query
+	// to a tenant without mutating stored configs
+	Tenant *TenantFilter
+
+	// ExactDecimals scans NUMERIC and DECIMAL columns as decimal.Decimal,
+	// see ScanOptions
+	ExactDecimals bool
+
+	// StatementTimeout, if positive, makes Postgres abort the query server-side
+	// after this long: the query runs in a read-only transaction that first
+	// sets statement_timeout locally. Other dialects fall back to a context
+	// deadline, which only cancels the query client-side.
+	StatementTimeout time.Duration
+
+	// QualifyColumns, if set, qualifies the config's bare column names with
+	// their tables from this snapshot before the query is built, see
+	// QualifyColumns. A column several tables have fails the query.
+	QualifyColumns *SchemaSnapshot
+}
+
 func ToSql(c *ChartConfig) (string, []interface{}, error) {
 	err := ValidateAndNormalizeConfig(c)
 	if err != nil {
@@ -13,3 +56,132 @@ func ToSql(c *ChartConfig) (string, []interface{}, error) {
 
 	return query, args, err
 }
+
+// CacheKey returns a stable SHA-256 hex key for the configuration. The key
+// hashes the generated SQL and its bound argument values, so two configs that
+// produce identical SQL and args share a key regardless of presentation
+// options or omitted defaults. Configs that fail to build are keyed by their
+// canonical JSON instead, including the Raw filters and tenant. The receiver
+// is not modified.
+func (c *ChartConfig) CacheKey() string {
+	h := sha256.New()
+	config := c.Clone()
+
+	if query, args, err := ToSql(config); err == nil {
+		argsJSON, err := json.Marshal(args)
+		if err == nil {
+			h.Write([]byte("sql\x00"))
+			h.Write([]byte(query))
+			h.Write([]byte("\x00"))
+			h.Write(argsJSON)
+			return hex.EncodeToString(h.Sum(nil))
+		}
+	}
+
+	// encoding/json emits struct fields in declaration order and sorts map
+	// keys, so this is canonical for a given config
+	canonical, _ := json.Marshal(struct {
+		Config *ChartConfig  `json:"config"`
+		Tenant *TenantFilter `json:"tenant"`
+	}{config, config.Tenant})
+	h.Write([]byte("config\x00"))
+	h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ExecuteChart builds, runs and scans a chart query. opts may be nil.
+func ExecuteChart(db *sqlx.DB, config *ChartConfig, opts *ExecuteOptions) ([]ChartDataRow, error) {
+	return ExecuteChartContext(context.Background(), db, config, opts)
+}
+
+// ExecuteChartContext is ExecuteChart with a context for cancellation
+func ExecuteChartContext(ctx context.Context, db *sqlx.DB, config *ChartConfig, opts *ExecuteOptions) ([]ChartDataRow, error) {
+	if opts != nil && opts.Tenant != nil {
+		config = config.Clone()
+		config.Tenant = opts.Tenant
+	}
+	if opts != nil && opts.QualifyColumns != nil {
+		qualified, err := QualifyColumns(config, opts.QualifyColumns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to qualify columns: %w", err)
+		}
+		config = qualified
+	}
+
+	query, args, err := ToSql(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts != nil && opts.QueryHook != nil {
+		query, args, err = opts.QueryHook(query, args)
+		if err != nil {
+			return nil, fmt.Errorf("query hook aborted execution: %w", err)
+		}
+	}
+
+	scan := ScanOptions{Config: config}
+	var timeout time.Duration
+	if opts != nil {
+		scan.ExactDecimals = opts.ExactDecimals
+		timeout = opts.StatementTimeout
+	}
+
+	if timeout > 0 && dialectOf(config) == DialectPostgres {
+		return queryWithStatementTimeout(ctx, db, timeout, query, args, scan)
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	rows, err := db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute chart query: %w", err)
+	}
+	defer rows.Close()
+
+	return ScanDynamicChartOptions(rows, scan)
+}
+
+// ExecuteChartWithTimeout is ExecuteChart with a server-side statement
+// timeout, see ExecuteOptions.StatementTimeout
+func ExecuteChartWithTimeout(db *sqlx.DB, config *ChartConfig, timeout time.Duration) ([]ChartDataRow, error) {
+	return ExecuteChartContext(context.Background(), db, config, &ExecuteOptions{StatementTimeout: timeout})
+}
+
+// queryWithStatementTimeout runs a query in a read-only transaction whose
+// statement_timeout is set locally, so it resets when the transaction ends
+func queryWithStatementTimeout(ctx context.Context, db *sqlx.DB, timeout time.Duration, query string, args []interface{}, scan ScanOptions) ([]ChartDataRow, error) {
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin chart query transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// SET LOCAL cannot take a bind parameter; set_config(..., true) is its
+	// parameterized equivalent. A zero timeout would disable the limit.
+	ms := timeout.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('statement_timeout', $1, true)", strconv.FormatInt(ms, 10)); err != nil {
+		return nil, fmt.Errorf("failed to set statement_timeout: %w", err)
+	}
+
+	rows, err := tx.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute chart query: %w", err)
+	}
+	results, err := ScanDynamicChartOptions(rows, scan)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit chart query transaction: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,36 @@
+package chatabase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTenantOverrideLeavesConfigUnchanged(t *testing.T) {
+	config := testConfig()
+	before := config.Clone()
+
+	var scopedQuery string
+	var scopedArgs []interface{}
+	stop := errors.New("stop")
+	opts := &ExecuteOptions{
+		Tenant: &TenantFilter{Column: "tenant_id", Value: 42},
+		// The hook aborts before the query runs, so no database is needed
+		QueryHook: func(query string, args []interface{}) (string, []interface{}, error) {
+			scopedQuery, scopedArgs = query, args
+			return "", nil, stop
+		},
+	}
+
+	if _, err := ExecuteChartContext(context.Background(), nil, config, opts); !errors.Is(err, stop) {
+		t.Fatalf("ExecuteChartContext() error = %v, want the hook error", err)
+	}
+	if !strings.Contains(scopedQuery, "tenant_id") || !reflect.DeepEqual(scopedArgs[len(scopedArgs)-1], 42) {
+		t.Errorf("query %q with args %v is not scoped to the tenant", scopedQuery, scopedArgs)
+	}
+	if !reflect.DeepEqual(config, before) {
+		t.Errorf("config changed to %+v, want %+v", config, before)
+	}
+}
@@ -2,286 +2,1051 @@ package chatabase
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
 func BuildChartQuery(config *ChartConfig) (string, []interface{}, error) {
+	query, b, err := buildChartQuery(config, newArgBinder(config))
+	if err != nil {
+		return "", nil, err
+	}
+	return query, b.args, nil
+}
+
+// BuildChartQueryIn is BuildChartQuery for sqlx.In: every placeholder is '?'
+// and each IN / NOT IN list is a single "column IN (?)" bound to the slice,
+// so large lists do not bloat the query. Expand and rebind before running:
+//
+//	query, args, err := BuildChartQueryIn(config)
+//	query, args, err = sqlx.In(query, args...)
+//	rows, err := db.Queryx(db.Rebind(query), args...)
+//
+// Multi-column IN filters stay expanded, and the array operators @> and <@
+// are rejected because sqlx.In would expand their array argument.
+func BuildChartQueryIn(config *ChartConfig) (string, []interface{}, error) {
+	b := newArgBinder(config)
+	b.inSlices = true
+
+	query, b, err := buildChartQuery(config, b)
+	if err != nil {
+		return "", nil, err
+	}
+	return query, b.args, nil
+}
+
+// BuildChartQueryNamed is BuildChartQuery with named placeholders :p1, :p2,
+// ... and the args keyed p1, p2, ..., for sqlx.NamedQuery. Raw filters and
+// subqueries must not contain '::' casts, which sqlx reads as an escaped colon.
+// Under DialectBigQuery the placeholders are @p1, @p2, ..., ready to pass as
+// bigquery.QueryParameter values named by the keys.
+func BuildChartQueryNamed(config *ChartConfig) (string, map[string]interface{}, error) {
+	b := newArgBinder(config)
+	b.named = true
+
+	query, b, err := buildChartQuery(config, b)
+	if err != nil {
+		return "", nil, err
+	}
+	return query, b.namedArgs(), nil
+}
+
+// buildChartQuery builds the chart SQL with b and returns it holding the args
+// and where each one came from
+func buildChartQuery(config *ChartConfig, b *argBinder) (string, *argBinder, error) {
+	query, b, err := buildSelectQuery(config, b)
+	if err != nil || !config.Options.AnnotateSQL {
+		return query, b, err
+	}
+	return sqlComment(config) + " " + query, b, nil
+}
+
+// sqlComment names the chart in a leading comment, so slow queries in
+// pg_stat_statements or the slow query log can be traced back to it:
+//
+//	/* chart='Revenue by month' chart_type='bar' */
+//
+// The values are sanitized so they cannot close the comment or be read as a
+// placeholder by sqlx.
+func sqlComment(config *ChartConfig) string {
+	return fmt.Sprintf("/* chart='%s' chart_type='%s' */", sanitizeComment(config.Title), sanitizeComment(config.ChartType))
+}
+
+// sanitizeComment drops the comment delimiters, the characters sqlx treats
+// as placeholders and any quote or control character from s
+func sanitizeComment(s string) string {
+	for strings.Contains(s, "/*") || strings.Contains(s, "*/") {
+		s = strings.NewReplacer("/*", "", "*/", "").Replace(s)
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '?' || r == ':' || r == '\'' || r == '\\':
+			return -1
+		case r < ' ' || r == 0x7f:
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// buildSelectQuery builds the chart SQL without the annotation comment
+func buildSelectQuery(config *ChartConfig, b *argBinder) (string, *argBinder, error) {
+	// BuildChartQuery may be called on an unvalidated config; fail as
+	// validation would rather than index into missing parts
+	if len(config.Tables) == 0 {
+		return "", nil, fmt.Errorf("at least one table is required")
+	}
+	if len(config.YAxis) == 0 {
+		return "", nil, fmt.Errorf("at least one y-axis is required")
+	}
+	if config.XAxis.Column == "" {
+		return "", nil, fmt.Errorf("x_axis column is required")
+	}
+
 	var query strings.Builder
-	var args []interface{}
-	argIndex := 1
 
 	// SELECT clause
 	query.WriteString("SELECT ")
+	if len(config.DistinctOn) > 0 {
+		if b.dialect != DialectPostgres {
+			return "", nil, fmt.Errorf("distinct_on is only supported by the postgres dialect")
+		}
+		query.WriteString(fmt.Sprintf("DISTINCT ON (%s) ", strings.Join(config.DistinctOn, ", ")))
+	}
 
 	// X-axis
-	if config.XAxis.Aggregation != "" {
-		query.WriteString(fmt.Sprintf("%s(%s) as x_value", config.XAxis.Aggregation, config.XAxis.Column))
-	} else {
-		query.WriteString(fmt.Sprintf("%s as x_value", config.XAxis.Column))
-	}
+	b.source = "x_axis.delimiter"
+	query.WriteString(fmt.Sprintf("%s as x_value", castExpr(config.XAxis.Cast, aggregateExpr(b, config.XAxis, xColumnExpr(config)))))
 
 	// Y-axis (multiple series support)
-	for _, yAxis := range config.YAxis {
+	for i, yAxis := range config.YAxis {
 		query.WriteString(", ")
-		if yAxis.Aggregation != "" {
-			query.WriteString(fmt.Sprintf("%s(%s) as %s", yAxis.Aggregation, yAxis.Column, yAxis.Alias))
-		} else {
-			query.WriteString(fmt.Sprintf("%s as %s", yAxis.Column, yAxis.Alias))
+		b.source = fmt.Sprintf("y_axis[%d].delimiter", i)
+		yColumn, err := seriesExpr(config, b, i, yAxis)
+		if err != nil {
+			return "", nil, err
+		}
+		yColumn = castExpr(yAxis.Cast, yColumn)
+		if yAxis.NullAs != nil {
+			b.source = fmt.Sprintf("y_axis[%d].null_as", i)
+			yColumn = fmt.Sprintf("COALESCE(%s, %s)", yColumn, b.bind(yAxis.NullAs))
 		}
+		query.WriteString(fmt.Sprintf("%s as %s", yColumn, yAlias(i, yAxis)))
+	}
+	// Gap filling counts the buckets in its outer query instead
+	if config.IncludeTotalCount && config.Options.GapFill == nil {
+		query.WriteString(", COUNT(*) OVER () as " + TotalCountColumn)
 	}
 
 	// FROM clause with joins
-	query.WriteString(fmt.Sprintf(" FROM %s", config.Tables[0].Name))
+	from, err := qualifyTable(config, config.Tables[0].Database, config.Tables[0].Name)
+	if err != nil {
+		return "", nil, err
+	}
+	query.WriteString(fmt.Sprintf(" FROM %s", from))
 	if config.Tables[0].Alias != "" {
 		query.WriteString(fmt.Sprintf(" %s", config.Tables[0].Alias))
 	}
+	if sample := config.Tables[0].Sample; sample != nil {
+		if b.dialect != DialectPostgres {
+			return "", nil, fmt.Errorf("table sampling is only supported by the postgres dialect")
+		}
+		query.WriteString(fmt.Sprintf(" TABLESAMPLE %s (%s)",
+			strings.ToUpper(sample.Method), strconv.FormatFloat(sample.Percent, 'f', -1, 64)))
+	}
 
-	// JOINs
-	for _, table := range config.Tables {
-		for _, join := range table.Joins {
-			query.WriteString(fmt.Sprintf(" %s JOIN %s", join.Type, join.Table))
-			if join.Alias != "" {
-				query.WriteString(fmt.Sprintf(" %s", join.Alias))
-			}
-			query.WriteString(fmt.Sprintf(" ON %s", join.Condition))
+	// JOINs: the first table's, then the top-level ones in declared order
+	for _, join := range tableJoins(config) {
+		joinTable, err := qualifyTable(config, join.Database, join.Table)
+		if err != nil {
+			return "", nil, err
+		}
+		query.WriteString(fmt.Sprintf(" %s JOIN %s", join.Type, joinTable))
+		if join.Alias != "" {
+			query.WriteString(fmt.Sprintf(" %s", join.Alias))
 		}
+		query.WriteString(fmt.Sprintf(" ON %s", join.Condition))
 	}
 
-	// WHERE clause with NULL handling
-	if len(config.Filters) > 0 {
+	// Further tables are comma tables, joined by the filters
+	for i, table := range config.Tables[1:] {
+		if len(table.Joins) > 0 {
+			return "", nil, fmt.Errorf("joins at table index %d must be declared on the first table", i+1)
+		}
+		name, err := qualifyTable(config, table.Database, table.Name)
+		if err != nil {
+			return "", nil, err
+		}
+		query.WriteString(", " + name)
+		if table.Alias != "" {
+			query.WriteString(" " + table.Alias)
+		}
+	}
+
+	// WHERE clause with NULL handling. The tenant predicate comes first and the
+	// user filters are parenthesized so no filter can widen it with OR.
+	if config.Tenant != nil || len(config.Filters) > 0 {
 		query.WriteString(" WHERE ")
-		for i, filter := range config.Filters {
+		if config.Tenant != nil {
+			b.source = "tenant"
+			predicate, err := tenantPredicate(config, b)
+			if err != nil {
+				return "", nil, err
+			}
+			query.WriteString(predicate)
+			if len(config.Filters) > 0 {
+				query.WriteString(" AND (")
+			}
+		}
+		where, err := buildWhere(config, b, "filters", config.Filters)
+		if err != nil {
+			return "", nil, err
+		}
+		query.WriteString(where)
+		if config.Tenant != nil && len(config.Filters) > 0 {
+			query.WriteString(")")
+		}
+	}
+
+	// GROUP BY
+	groupBy, err := groupByClause(config)
+	if err != nil {
+		return "", nil, err
+	}
+	query.WriteString(groupBy)
+
+	// HAVING. Postgres does not resolve select aliases here, so a Y-axis
+	// alias is replaced by the axis's aggregate expression.
+	if len(config.Having) > 0 {
+		query.WriteString(" HAVING ")
+		for i, filter := range config.Having {
 			if i > 0 {
 				query.WriteString(" AND ")
 			}
+			b.source = fmt.Sprintf("having[%d]", i)
+			filter.Column, err = havingExpr(config, b, filter.Column)
+			if err != nil {
+				return "", nil, fmt.Errorf("having: %w", err)
+			}
+			predicate, err := buildFilter(config, b, i, filter)
+			if err != nil {
+				return "", nil, fmt.Errorf("having: %w", err)
+			}
+			query.WriteString(predicate)
+		}
+	}
 
-			// 🌟 NEW: raw predicate support
-			if filter.Raw != "" {
-				// recommended: write Raw with '?' placeholders and we convert them to $1, $2,...
-				processedRaw := replaceQuestionMarksWithDollarPlaceholders(filter.Raw, &argIndex)
-				query.WriteString(processedRaw)
+	// Gap filling replaces ORDER BY and LIMIT with its own over the buckets
+	if config.Options.GapFill != nil {
+		return buildGapFillQuery(config, b, query.String())
+	}
 
-				// append RawValues in order
-				for _, v := range filter.RawValues {
-					args = append(args, v)
-				}
-				// argIndex was already bumped inside helper
-				continue
+	// ORDER BY
+	if len(config.OrderBy) > 0 {
+		query.WriteString(" ORDER BY ")
+		var orderClauses []string
+		for _, order := range config.OrderBy {
+			orderClauses = append(orderClauses, fmt.Sprintf("%s %s", order.orderExpr(), order.Direction))
+		}
+		query.WriteString(strings.Join(orderClauses, ", "))
+	}
+
+	// LIMIT
+	query.WriteString(limitClause(config))
+
+	return query.String(), b, nil
+}
+
+// limitClause returns " LIMIT n", or " FETCH FIRST n ROWS WITH TIES" when
+// ties are kept, and "" without a limit
+func limitClause(config *ChartConfig) string {
+	if config.Limit <= 0 {
+		return ""
+	}
+	if config.WithTies {
+		return fmt.Sprintf(" FETCH FIRST %d ROWS WITH TIES", config.Limit)
+	}
+	return fmt.Sprintf(" LIMIT %d", config.Limit)
+}
+
+// aggregateExpr applies an aggregation to a column expression. PCT is the
+// share of the grand total, a window over the grouped sums:
+//
+//	100.0 * SUM(amount) / NULLIF(SUM(SUM(amount)) OVER (), 0)
+//
+// The inner SUM runs per group and the outer SUM ... OVER () adds the group
+// sums across the whole result, so the slices of a pie add up to 100. NULLIF
+// yields NULL rather than a division error when the total is zero.
+//
+// MEDIAN and PERCENTILE are ordered-set aggregates, so the column goes in
+// WITHIN GROUP rather than the call: percentile_cont(0.5) WITHIN GROUP
+// (ORDER BY amount). The validated percentile is a float and safe to inline.
+// MODE is ordered-set too. STRING_AGG binds its delimiter with b, or quotes
+// it when b is nil, as when describing a config. COUNT of an empty column
+// counts rows, COUNT(*), unlike COUNT(column) which skips NULLs.
+//
+// ClickHouse spells these median(amount), quantile(0.9)(amount), and
+// stddevSamp and varSamp for the sample statistics Postgres computes.
+func aggregateExpr(b *argBinder, axis AxisConfig, column string) string {
+	switch axis.Aggregation {
+	case "":
+		return column
+	case "COUNT":
+		if column == "" {
+			column = "*"
+		}
+		return fmt.Sprintf("COUNT(%s)", column)
+	case AggregationPercent:
+		return fmt.Sprintf("100.0 * SUM(%s) / NULLIF(SUM(SUM(%s)) OVER (), 0)", column, column)
+	case AggregationMedian:
+		if b != nil && b.dialect == DialectClickHouse {
+			return fmt.Sprintf("median(%s)", column)
+		}
+		return fmt.Sprintf("percentile_cont(0.5) WITHIN GROUP (ORDER BY %s)", column)
+	case AggregationPercentile:
+		percentile := strconv.FormatFloat(axis.Percentile, 'f', -1, 64)
+		if b != nil && b.dialect == DialectClickHouse {
+			return fmt.Sprintf("quantile(%s)(%s)", percentile, column)
+		}
+		return fmt.Sprintf("percentile_cont(%s) WITHIN GROUP (ORDER BY %s)", percentile, column)
+	case "STDDEV", "VARIANCE":
+		if b != nil && b.dialect == DialectClickHouse {
+			function := "stddevSamp"
+			if axis.Aggregation == "VARIANCE" {
+				function = "varSamp"
 			}
+			return fmt.Sprintf("%s(%s)", function, column)
+		}
+		return fmt.Sprintf("%s(%s)", axis.Aggregation, column)
+	case AggregationMode:
+		return fmt.Sprintf("mode() WITHIN GROUP (ORDER BY %s)", column)
+	case AggregationStringAgg:
+		delimiter := axis.Delimiter
+		if delimiter == "" {
+			delimiter = DefaultStringAggDelimiter
+		}
+		if b == nil {
+			return fmt.Sprintf("string_agg(%s, %s)", column, quoteLiteral(delimiter))
+		}
+		if b.dialect == DialectSQLite {
+			return fmt.Sprintf("group_concat(%s, %s)", column, b.bind(delimiter))
+		}
+		return fmt.Sprintf("string_agg(%s, %s)", column, b.bind(delimiter))
+	default:
+		return fmt.Sprintf("%s(%s)", axis.Aggregation, column)
+	}
+}
 
-			switch strings.ToLower(filter.Operator) {
-			case "in":
-				// Handle NULL values in IN clause
-				var nullCount int
-				var nonNullValues []interface{}
-
-				for _, val := range filter.Values {
-					if val == nil {
-						nullCount++
-					} else {
-						nonNullValues = append(nonNullValues, val)
-					}
-				}
+// seriesExpr returns the expression the Y axis at index selects, before its
+// cast and NULL default: the aggregated column, restricted by its series
+// filter, or the derived operation
+func seriesExpr(config *ChartConfig, b *argBinder, index int, axis AxisConfig) (string, error) {
+	if axis.Numerator == nil || axis.Denominator == nil {
+		if len(axis.SeriesFilter) > 0 {
+			return filteredAggregateExpr(config, b, index, axis)
+		}
+		return aggregateExpr(b, axis, columnExpr(axis.Column, axis.JSONPath)), nil
+	}
+	if len(axis.SeriesFilter) > 0 {
+		return "", fmt.Errorf("series_filter for y_axis at index %d cannot be combined with a derived series", index)
+	}
 
-				if nullCount > 0 && len(nonNullValues) > 0 {
-					// Mix of NULL and non-NULL values
-					query.WriteString("(")
-					placeholders := make([]string, len(nonNullValues))
-					for j, val := range nonNullValues {
-						placeholders[j] = fmt.Sprintf("$%d", argIndex)
-						args = append(args, val)
-						argIndex++
-					}
-					query.WriteString(fmt.Sprintf("%s IN (%s) OR %s IS NULL",
-						filter.Column, strings.Join(placeholders, ", "), filter.Column))
-					query.WriteString(")")
-				} else if nullCount > 0 {
-					// Only NULL values
-					query.WriteString(fmt.Sprintf("%s IS NULL", filter.Column))
-				} else {
-					// Only non-NULL values
-					placeholders := make([]string, len(nonNullValues))
-					for j, val := range nonNullValues {
-						placeholders[j] = fmt.Sprintf("$%d", argIndex)
-						args = append(args, val)
-						argIndex++
-					}
-					query.WriteString(fmt.Sprintf("%s IN (%s)", filter.Column, strings.Join(placeholders, ", ")))
-				}
+	numerator := aggregateExpr(b, AxisConfig{Aggregation: axis.Numerator.Aggregation}, axis.Numerator.Column)
+	denominator := aggregateExpr(b, AxisConfig{Aggregation: axis.Denominator.Aggregation}, axis.Denominator.Column)
+	switch axis.Operation {
+	case "", "/":
+		// Integer operands would truncate, and a zero denominator would fail
+		// the whole query, so divide in floating point by NULLIF(..., 0)
+		if b != nil && b.dialect != DialectPostgres {
+			return fmt.Sprintf("1.0 * %s / NULLIF(%s, 0)", numerator, denominator), nil
+		}
+		return fmt.Sprintf("CAST(%s AS double precision) / NULLIF(%s, 0)", numerator, denominator), nil
+	default:
+		return fmt.Sprintf("%s %s %s", numerator, axis.Operation, denominator), nil
+	}
+}
 
-			case "not in":
-				// Handle NULL values in NOT IN clause
-				var nullCount int
-				var nonNullValues []interface{}
-
-				for _, val := range filter.Values {
-					if val == nil {
-						nullCount++
-					} else {
-						nonNullValues = append(nonNullValues, val)
-					}
-				}
+// tableJoins returns the joins of the first table followed by config.Joins,
+// in the order they are emitted
+func tableJoins(config *ChartConfig) []JoinConfig {
+	joins := config.Tables[0].Joins
+	if len(config.Joins) == 0 {
+		return joins
+	}
+	return append(append([]JoinConfig{}, joins...), config.Joins...)
+}
 
-				if nullCount > 0 && len(nonNullValues) > 0 {
-					// Mix of NULL and non-NULL values
-					query.WriteString("(")
-					placeholders := make([]string, len(nonNullValues))
-					for j, val := range nonNullValues {
-						placeholders[j] = fmt.Sprintf("$%d", argIndex)
-						args = append(args, val)
-						argIndex++
-					}
-					query.WriteString(fmt.Sprintf("%s NOT IN (%s) AND %s IS NOT NULL",
-						filter.Column, strings.Join(placeholders, ", "), filter.Column))
-					query.WriteString(")")
-				} else if nullCount > 0 {
-					// Only NULL values - NOT IN NULL means everything except NULL
-					query.WriteString(fmt.Sprintf("%s IS NOT NULL", filter.Column))
-				} else {
-					// Only non-NULL values
-					placeholders := make([]string, len(nonNullValues))
-					for j, val := range nonNullValues {
-						placeholders[j] = fmt.Sprintf("$%d", argIndex)
-						args = append(args, val)
-						argIndex++
-					}
-					query.WriteString(fmt.Sprintf("(%s NOT IN (%s) OR %s IS NULL)",
-						filter.Column, strings.Join(placeholders, ", "), filter.Column))
-				}
+// castExpr wraps expr in CAST(expr AS cast) when a cast is set
+func castExpr(cast, expr string) string {
+	if cast == "" {
+		return expr
+	}
+	return fmt.Sprintf("CAST(%s AS %s)", expr, cast)
+}
 
-			case "between":
-				// BETWEEN with NULL handling
-				if filter.Values[0] == nil || filter.Values[1] == nil {
-					return "", nil, fmt.Errorf("BETWEEN operator cannot have NULL values")
-				}
-				query.WriteString(fmt.Sprintf("%s BETWEEN $%d AND $%d", filter.Column, argIndex, argIndex+1))
-				args = append(args, filter.Values[0], filter.Values[1])
-				argIndex += 2
-
-			case "=", "!=", "<>":
-				// Handle NULL comparison
-				if filter.Value == nil {
-					if strings.ToLower(filter.Operator) == "=" {
-						query.WriteString(fmt.Sprintf("%s IS NULL", filter.Column))
-					} else {
-						query.WriteString(fmt.Sprintf("%s IS NOT NULL", filter.Column))
-					}
-				} else {
-					// Handle boolean comparison
-					if boolVal, ok := filter.Value.(string); ok {
-						lowerVal := strings.ToLower(boolVal)
-						if lowerVal == "true" || lowerVal == "false" {
-							if strings.ToLower(filter.Operator) == "=" {
-								if lowerVal == "true" {
-									query.WriteString(fmt.Sprintf("%s IS TRUE", filter.Column))
-								} else {
-									query.WriteString(fmt.Sprintf("%s IS FALSE", filter.Column))
-								}
-							} else { // != or <>
-								if lowerVal == "true" {
-									query.WriteString(fmt.Sprintf("%s IS NOT TRUE", filter.Column))
-								} else {
-									query.WriteString(fmt.Sprintf("%s IS NOT FALSE", filter.Column))
-								}
-							}
-						} else {
-							query.WriteString(fmt.Sprintf("%s %s $%d", filter.Column, filter.Operator, argIndex))
-							args = append(args, filter.Value)
-							argIndex++
-						}
-					} else if boolVal, ok := filter.Value.(bool); ok {
-						// Handle actual boolean type
-						if strings.ToLower(filter.Operator) == "=" {
-							if boolVal {
-								query.WriteString(fmt.Sprintf("%s IS TRUE", filter.Column))
-							} else {
-								query.WriteString(fmt.Sprintf("%s IS FALSE", filter.Column))
-							}
-						} else { // != or <>
-							if boolVal {
-								query.WriteString(fmt.Sprintf("%s IS NOT TRUE", filter.Column))
-							} else {
-								query.WriteString(fmt.Sprintf("%s IS NOT FALSE", filter.Column))
-							}
-						}
-					} else {
-						query.WriteString(fmt.Sprintf("%s %s $%d", filter.Column, filter.Operator, argIndex))
-						args = append(args, filter.Value)
-						argIndex++
-					}
-				}
+// yAlias returns the result column name of the Y axis at index i, defaulting
+// to y_value_1, y_value_2, ... when no alias is set
+func yAlias(i int, axis AxisConfig) string {
+	if axis.Alias != "" {
+		return axis.Alias
+	}
+	return fmt.Sprintf("y_value_%d", i+1)
+}
 
-			case "is null":
-				query.WriteString(fmt.Sprintf("%s IS NULL", filter.Column))
+// BuildWhereClause renders filters as a WHERE fragment, without the WHERE
+// keyword, for hand-written queries. It applies the same NULL, boolean and IN
+// handling as BuildChartQuery, uses Postgres placeholders numbered from
+// startArgIndex and returns the args in placeholder order.
+func BuildWhereClause(filters []FilterConfig, startArgIndex int) (string, []interface{}, error) {
+	if startArgIndex < 1 {
+		return "", nil, fmt.Errorf("startArgIndex must be at least 1, got %d", startArgIndex)
+	}
+	for i := range filters {
+		if err := validateFilter(&filters[i], i); err != nil {
+			return "", nil, err
+		}
+	}
 
-			case "is not null":
-				query.WriteString(fmt.Sprintf("%s IS NOT NULL", filter.Column))
+	config := &ChartConfig{Filters: filters}
+	b := newArgBinder(config)
+	b.next = startArgIndex
 
-			case "<", "<=", ">", ">=":
-				// Comparison operators with NULL values
-				if filter.Value == nil {
-					return "", nil, fmt.Errorf("comparison operator %s cannot compare with NULL", filter.Operator)
-				}
-				query.WriteString(fmt.Sprintf("%s %s $%d", filter.Column, filter.Operator, argIndex))
-				args = append(args, filter.Value)
-				argIndex++
-
-			case "like", "ilike", "not like", "not ilike":
-				// LIKE operators with NULL handling
-				if filter.Value == nil {
-					query.WriteString(fmt.Sprintf("%s IS NULL", filter.Column))
-				} else {
-					query.WriteString(fmt.Sprintf("%s %s $%d", filter.Column, filter.Operator, argIndex))
-					args = append(args, filter.Value)
-					argIndex++
-				}
+	where, err := buildWhere(config, b, "filters", filters)
+	if err != nil {
+		return "", nil, err
+	}
+	return where, b.args, nil
+}
+
+// buildWhere joins the predicates of filters with AND. field names the
+// config field the filters come from, e.g. "filters", for the arg sources.
+func buildWhere(config *ChartConfig, b *argBinder, field string, filters []FilterConfig) (string, error) {
+	predicates := make([]string, len(filters))
+	for i, filter := range filters {
+		b.source = fmt.Sprintf("%s[%d]", field, i)
+		column, err := whereExpr(config, b, filter.Column)
+		if err != nil {
+			return "", err
+		}
+		filter.Column = column
+		predicate, err := buildFilter(config, b, i, filter)
+		if err != nil {
+			return "", err
+		}
+		predicates[i] = predicate
+	}
+	return strings.Join(predicates, " AND "), nil
+}
+
+// buildFilter renders the WHERE predicate for the filter at index i
+func buildFilter(config *ChartConfig, b *argBinder, i int, filter FilterConfig) (string, error) {
+	var query strings.Builder
+
+	// 🌟 NEW: raw predicate support
+	if filter.Raw != "" {
+		if config.Tenant != nil && !isSelfContainedSQL(filter.Raw) {
+			return "", fmt.Errorf("raw filter at index %d must be a self-contained expression when a tenant filter is set", i)
+		}
+		// recommended: write Raw with '?' placeholders and we convert them to the dialect's markers ($1, $2,... for Postgres)
+		query.WriteString(b.bindRaw(filter.Raw, filter.RawValues))
+		return query.String(), nil
+	}
+
+	if filter.Subquery != "" && config.Tenant != nil && !isSelfContainedSQL(filter.Subquery) {
+		return "", fmt.Errorf("subquery at filter index %d must be a self-contained expression when a tenant filter is set", i)
+	}
+
+	// Existence check: EXISTS (SELECT 1 FROM refunds r WHERE r.order_id = o.id)
+	if isExistsOperator(filter.Operator) {
+		if filter.Subquery == "" {
+			return "", fmt.Errorf("%s operator requires a subquery", filter.Operator)
+		}
+		subquery, err := b.bindSubquery(filter.Subquery, filter.SubqueryArgs)
+		if err != nil {
+			return "", fmt.Errorf("filter at index %d: %w", i, err)
+		}
+		query.WriteString(fmt.Sprintf("%s (%s)",
+			strings.Join(strings.Fields(strings.ToUpper(filter.Operator)), " "), subquery))
+		return query.String(), nil
+	}
+
+	// Subquery comparison: column > ALL (SELECT ...)
+	if isSubqueryOperator(filter.Operator) {
+		if filter.Subquery == "" {
+			return "", fmt.Errorf("%s operator requires a subquery", filter.Operator)
+		}
+		subquery, err := b.bindSubquery(filter.Subquery, filter.SubqueryArgs)
+		if err != nil {
+			return "", fmt.Errorf("filter at index %d: %w", i, err)
+		}
+		fields := strings.Fields(strings.ToUpper(filter.Operator))
+		query.WriteString(fmt.Sprintf("%s %s %s (%s)",
+			columnExpr(filter.Column, filter.JSONPath), fields[0], fields[1], subquery))
+		return query.String(), nil
+	}
+
+	// Multi-column IN: (col1, col2) IN (($1, $2), ($3, $4))
+	if len(filter.Columns) > 0 {
+		if len(filter.Tuples) == 0 {
+			return "", fmt.Errorf("multi-column IN requires at least one tuple")
+		}
+		tuples := make([]string, len(filter.Tuples))
+		for j, tuple := range filter.Tuples {
+			if len(tuple) != len(filter.Columns) {
+				return "", fmt.Errorf("tuple at index %d has %d values, expected %d",
+					j, len(tuple), len(filter.Columns))
+			}
+			placeholders := make([]string, len(tuple))
+			for k, val := range tuple {
+				placeholders[k] = b.bind(val)
+			}
+			tuples[j] = "(" + strings.Join(placeholders, ", ") + ")"
+		}
+		operator := "IN"
+		if strings.ToLower(filter.Operator) == "not in" {
+			operator = "NOT IN"
+		}
+		query.WriteString(fmt.Sprintf("(%s) %s (%s)",
+			strings.Join(filter.Columns, ", "), operator, strings.Join(tuples, ", ")))
+		return query.String(), nil
+	}
+
+	column := columnExpr(filter.Column, filter.JSONPath)
+
+	// Relative date window: created_at >= now() - make_interval(days => $1)
+	if filter.Relative != nil {
+		// The unit is written into the SQL, so check it even without validation
+		if !contains(ValidRelativeUnits, filter.Relative.Unit) {
+			return "", fmt.Errorf("invalid relative filter unit '%s' at index %d", filter.Relative.Unit, i)
+		}
+		query.WriteString(relativeDateExpr(b, column, *filter.Relative))
+		return query.String(), nil
+	}
+
+	// BuildChartQuery may be called on an unvalidated config, so check the
+	// values BETWEEN indexes rather than panic, and keep IN from rendering
+	// an empty list
+	switch operator := strings.Join(strings.Fields(strings.ToUpper(filter.Operator)), " "); operator {
+	case "BETWEEN":
+		if len(filter.Values) != 2 {
+			return "", fmt.Errorf("BETWEEN operator requires exactly 2 values at filter index %d, got %d", i, len(filter.Values))
+		}
+	case "IN", "NOT IN":
+		if len(filter.Values) == 0 {
+			return "", fmt.Errorf("%s operator requires 'values' array at filter index %d", operator, i)
+		}
+	}
+
+	// Trusted expression operand: created_at > (now() - interval '7 days')
+	if filter.ValueExpr != "" {
+		if !isSelfContainedSQL(filter.ValueExpr) {
+			return "", fmt.Errorf("value_expr at filter index %d must be a self-contained expression", i)
+		}
+		query.WriteString(fmt.Sprintf("%s %s (%s)", column, filter.Operator, filter.ValueExpr))
+		return query.String(), nil
+	}
+
+	// Strict NULL handling: the operator as written, NULLs bound as values
+	if config.NullHandling == NullHandlingStrict {
+		switch operator := strings.Join(strings.Fields(strings.ToUpper(filter.Operator)), " "); operator {
+		case "IN", "NOT IN":
+			return fmt.Sprintf("%s %s (%s)", column, operator, b.bindList(filter.Values)), nil
+		case "BETWEEN":
+			return fmt.Sprintf("%s BETWEEN %s AND %s", column, b.bind(filter.Values[0]), b.bind(filter.Values[1])), nil
+		case "=", "!=", "<>":
+			return fmt.Sprintf("%s %s %s", column, operator, b.bind(filter.Value)), nil
+		}
+	}
+
+	switch strings.ToLower(filter.Operator) {
+	case "in":
+		// Handle NULL values in IN clause
+		var nullCount int
+		var nonNullValues []interface{}
+
+		for _, val := range filter.Values {
+			if val == nil {
+				nullCount++
+			} else {
+				nonNullValues = append(nonNullValues, val)
+			}
+		}
+
+		if nullCount > 0 && len(nonNullValues) > 0 {
+			// Mix of NULL and non-NULL values
+			query.WriteString("(")
+			placeholders := b.bindList(nonNullValues)
+			query.WriteString(fmt.Sprintf("%s IN (%s) OR %s IS NULL",
+				column, placeholders, column))
+			query.WriteString(")")
+		} else if nullCount > 0 {
+			// Only NULL values
+			query.WriteString(fmt.Sprintf("%s IS NULL", column))
+		} else {
+			// Only non-NULL values
+			placeholders := b.bindList(nonNullValues)
+			query.WriteString(fmt.Sprintf("%s IN (%s)", column, placeholders))
+		}
 
-			default:
-				// Default case - handle NULL values
-				if filter.Value == nil {
-					query.WriteString(fmt.Sprintf("%s IS NULL", filter.Column))
+	case "not in":
+		// Handle NULL values in NOT IN clause
+		var nullCount int
+		var nonNullValues []interface{}
+
+		for _, val := range filter.Values {
+			if val == nil {
+				nullCount++
+			} else {
+				nonNullValues = append(nonNullValues, val)
+			}
+		}
+
+		if nullCount > 0 && len(nonNullValues) > 0 {
+			// Mix of NULL and non-NULL values
+			query.WriteString("(")
+			placeholders := b.bindList(nonNullValues)
+			query.WriteString(fmt.Sprintf("%s NOT IN (%s) AND %s IS NOT NULL",
+				column, placeholders, column))
+			query.WriteString(")")
+		} else if nullCount > 0 {
+			// Only NULL values - NOT IN NULL means everything except NULL
+			query.WriteString(fmt.Sprintf("%s IS NOT NULL", column))
+		} else {
+			// Only non-NULL values
+			placeholders := b.bindList(nonNullValues)
+			query.WriteString(fmt.Sprintf("(%s NOT IN (%s) OR %s IS NULL)",
+				column, placeholders, column))
+		}
+
+	case "between":
+		// BETWEEN with NULL handling
+		if filter.Values[0] == nil || filter.Values[1] == nil {
+			return "", fmt.Errorf("BETWEEN operator cannot have NULL values")
+		}
+		query.WriteString(fmt.Sprintf("%s BETWEEN %s AND %s", column, b.bind(filter.Values[0]), b.bind(filter.Values[1])))
+
+	case "=", "!=", "<>":
+		// Handle NULL comparison
+		if filter.Value == nil {
+			if strings.ToLower(filter.Operator) == "=" {
+				query.WriteString(fmt.Sprintf("%s IS NULL", column))
+			} else {
+				query.WriteString(fmt.Sprintf("%s IS NOT NULL", column))
+			}
+		} else {
+			// Handle boolean comparison
+			if boolVal, ok := filter.Value.(string); ok {
+				lowerVal := strings.ToLower(boolVal)
+				if lowerVal == "true" || lowerVal == "false" {
+					query.WriteString(boolTest(b.dialect, column, lowerVal == "true", strings.ToLower(filter.Operator) == "="))
 				} else {
-					query.WriteString(fmt.Sprintf("%s %s $%d", filter.Column, filter.Operator, argIndex))
-					args = append(args, filter.Value)
-					argIndex++
+					query.WriteString(fmt.Sprintf("%s %s %s", column, filter.Operator, b.bind(filter.Value)))
 				}
+			} else if boolVal, ok := filter.Value.(bool); ok {
+				// Handle actual boolean type
+				query.WriteString(boolTest(b.dialect, column, boolVal, strings.ToLower(filter.Operator) == "="))
+			} else {
+				query.WriteString(fmt.Sprintf("%s %s %s", column, filter.Operator, b.bind(filter.Value)))
 			}
 		}
+
+	case "@>", "<@":
+		if b.dialect != DialectPostgres {
+			return "", fmt.Errorf("%s operator is only supported by the postgres dialect", filter.Operator)
+		}
+		// Postgres-only array containment, e.g. tags @> $1 with $1 = {'urgent'}
+		if len(filter.Values) == 0 {
+			return "", fmt.Errorf("%s operator requires at least one value", filter.Operator)
+		}
+		if b.inSlices {
+			return "", fmt.Errorf("%s operator cannot be used with sqlx.In expansion", filter.Operator)
+		}
+		query.WriteString(fmt.Sprintf("%s %s %s", column, filter.Operator, b.bind(arrayArg(filter.Values))))
+
+	case "any":
+		if b.dialect != DialectPostgres {
+			return "", fmt.Errorf("ANY operator is only supported by the postgres dialect")
+		}
+		// Postgres-only array membership, e.g. $1 = ANY(tags)
+		if filter.Value == nil {
+			return "", fmt.Errorf("ANY operator cannot match NULL")
+		}
+		query.WriteString(fmt.Sprintf("%s = ANY(%s)", b.bind(filter.Value), column))
+
+	case "is", "is not", "is null", "is not null":
+		expr, err := isExpr(b.dialect, column, filter)
+		if err != nil {
+			return "", fmt.Errorf("filter at index %d: %w", i, err)
+		}
+		query.WriteString(expr)
+
+	case "<", "<=", ">", ">=":
+		// Comparison operators with NULL values
+		if filter.Value == nil {
+			return "", fmt.Errorf("comparison operator %s cannot compare with NULL", filter.Operator)
+		}
+		query.WriteString(fmt.Sprintf("%s %s %s", column, filter.Operator, b.bind(filter.Value)))
+
+	case "like", "ilike", "not like", "not ilike":
+		// LIKE operators with NULL handling
+		if filter.Value == nil {
+			query.WriteString(fmt.Sprintf("%s IS NULL", column))
+		} else {
+			query.WriteString(fmt.Sprintf("%s %s %s", column, filter.Operator, b.bind(filter.Value)))
+		}
+
+	default:
+		// Default case - handle NULL values
+		if filter.Value == nil {
+			query.WriteString(fmt.Sprintf("%s IS NULL", column))
+		} else {
+			query.WriteString(fmt.Sprintf("%s %s %s", column, filter.Operator, b.bind(filter.Value)))
+		}
 	}
 
-	// GROUP BY
-	if len(config.GroupBy) > 0 {
-		query.WriteString(" GROUP BY ")
-		query.WriteString(strings.Join(config.GroupBy, ", "))
+	return query.String(), nil
+}
+
+// xColumnExpr returns the X axis expression before aggregation and cast: the
+// column, its JSON path extraction or its time bucket
+func xColumnExpr(config *ChartConfig) string {
+	if config.Options.GapFill != nil {
+		return bucketExpr(config)
 	}
+	return columnExpr(config.XAxis.Column, config.XAxis.JSONPath)
+}
 
-	// ORDER BY
-	if len(config.OrderBy) > 0 {
-		query.WriteString(" ORDER BY ")
-		var orderClauses []string
-		for _, order := range config.OrderBy {
-			orderClauses = append(orderClauses, fmt.Sprintf("%s %s", order.Column, order.Direction))
+// whereExpr resolves a WHERE column. WHERE is evaluated before the select
+// list, so x_value and the alias of an unaggregated Y axis become the
+// expression they select: "x_value" -> DATE_TRUNC('month', created_at).
+// Validation rejects aliases of aggregated axes.
+func whereExpr(config *ChartConfig, b *argBinder, column string) (string, error) {
+	if column == "x_value" && config.XAxis.Column != "" && config.XAxis.Aggregation == "" {
+		return castExpr(config.XAxis.Cast, xColumnExpr(config)), nil
+	}
+	for i, yAxis := range config.YAxis {
+		if yAlias(i, yAxis) != column || yAxis.Column == column || yAxis.aggregated() {
+			continue
 		}
-		query.WriteString(strings.Join(orderClauses, ", "))
+		return aliasExpr(config, b, i, yAxis)
 	}
+	return column, nil
+}
 
-	// LIMIT
-	if config.Limit > 0 {
-		query.WriteString(fmt.Sprintf(" LIMIT %d", config.Limit))
+// havingExpr resolves a HAVING column. The alias of an aggregated Y axis
+// becomes the expression it selects: "total" -> SUM(amount).
+func havingExpr(config *ChartConfig, b *argBinder, column string) (string, error) {
+	for i, yAxis := range config.YAxis {
+		if yAlias(i, yAxis) != column || !yAxis.aggregated() {
+			continue
+		}
+		return aliasExpr(config, b, i, yAxis)
 	}
+	return column, nil
+}
 
-	return query.String(), args, nil
+// aliasExpr returns the expression the Y axis at index selects, with its
+// cast and NULL default, in place of its alias
+func aliasExpr(config *ChartConfig, b *argBinder, index int, axis AxisConfig) (string, error) {
+	expr, err := seriesExpr(config, b, index, axis)
+	if err != nil {
+		return "", err
+	}
+	expr = castExpr(axis.Cast, expr)
+	if axis.NullAs != nil {
+		expr = fmt.Sprintf("COALESCE(%s, %s)", expr, b.bind(axis.NullAs))
+	}
+	return expr, nil
 }
 
-func replaceQuestionMarksWithDollarPlaceholders(raw string, argIndex *int) string {
-	var b strings.Builder
-	for _, ch := range raw {
-		if ch == '?' {
-			b.WriteString(fmt.Sprintf("$%d", *argIndex))
-			*argIndex++
-		} else {
-			b.WriteRune(ch)
+// isExpr renders an IS filter: IS NULL for a NULL value and IS TRUE / IS
+// FALSE for a boolean, true or false also given as a string. "IS NULL" and
+// "IS NOT NULL" carry their operand in the operator and take no value.
+func isExpr(dialect, column string, filter FilterConfig) (string, error) {
+	operator := strings.Join(strings.Fields(strings.ToUpper(filter.Operator)), " ")
+	value := filter.Value
+	if operator == "IS NULL" || operator == "IS NOT NULL" {
+		if value != nil {
+			return "", fmt.Errorf("%s operator takes no value, got %v", operator, value)
+		}
+		operator = strings.TrimSuffix(operator, " NULL")
+	}
+	not := operator == "IS NOT"
+
+	if value == nil {
+		if not {
+			return fmt.Sprintf("%s IS NOT NULL", column), nil
+		}
+		return fmt.Sprintf("%s IS NULL", column), nil
+	}
+	if s, ok := value.(string); ok {
+		switch strings.ToLower(s) {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		}
+	}
+	if boolVal, ok := value.(bool); ok {
+		return boolTest(dialect, column, boolVal, !not), nil
+	}
+	return "", fmt.Errorf("%s operator requires a NULL or boolean value, got %v", operator, value)
+}
+
+// boolTest renders "column IS [NOT] TRUE/FALSE". A NULL column is neither
+// true nor false, so IS NOT TRUE matches it. ClickHouse has no IS TRUE and
+// gets the equivalent comparisons.
+func boolTest(dialect, column string, value, equal bool) string {
+	if dialect == DialectClickHouse {
+		if equal {
+			return fmt.Sprintf("%s = %t", column, value)
+		}
+		return fmt.Sprintf("(%s != %t OR %s IS NULL)", column, value, column)
+	}
+
+	test := "FALSE"
+	if value {
+		test = "TRUE"
+	}
+	if !equal {
+		test = "NOT " + test
+	}
+	return fmt.Sprintf("%s IS %s", column, test)
+}
+
+// columnExpr returns the SQL expression for a column, applying a JSONB path
+// extraction when one is set: data->>'source' or data#>>'{"a","b"}'
+func columnExpr(column string, jsonPath []string) string {
+	switch len(jsonPath) {
+	case 0:
+		return column
+	case 1:
+		return fmt.Sprintf("%s->>%s", column, quoteLiteral(jsonPath[0]))
+	default:
+		elements := make([]string, len(jsonPath))
+		for i, key := range jsonPath {
+			key = strings.ReplaceAll(key, `\`, `\\`)
+			key = strings.ReplaceAll(key, `"`, `\"`)
+			elements[i] = `"` + key + `"`
+		}
+		return fmt.Sprintf("%s#>>%s", column, quoteLiteral("{"+strings.Join(elements, ",")+"}"))
+	}
+}
+
+// groupByClause renders the GROUP BY clause for the config's GroupByMode:
+// GROUP BY a, b / ROLLUP(a, b) / CUBE(a, b) / GROUPING SETS ((a, b), (a), ())
+func groupByClause(config *ChartConfig) (string, error) {
+	list := func(columns []string) string {
+		exprs := make([]string, len(columns))
+		for i, column := range columns {
+			exprs[i] = groupByExpr(config, column)
+		}
+		return strings.Join(exprs, ", ")
+	}
+
+	dialect := dialectOf(config)
+	switch config.GroupByMode {
+	case "", GroupByModePlain:
+		if len(config.GroupBy) == 0 {
+			return "", nil
+		}
+		return " GROUP BY " + list(config.GroupBy), nil
+
+	case GroupByModeRollup:
+		switch dialect {
+		case DialectPostgres, DialectBigQuery:
+			return fmt.Sprintf(" GROUP BY ROLLUP(%s)", list(config.GroupBy)), nil
+		case DialectMySQL, DialectClickHouse:
+			return fmt.Sprintf(" GROUP BY %s WITH ROLLUP", list(config.GroupBy)), nil
+		}
+
+	case GroupByModeCube:
+		switch dialect {
+		case DialectPostgres, DialectBigQuery:
+			return fmt.Sprintf(" GROUP BY CUBE(%s)", list(config.GroupBy)), nil
+		case DialectClickHouse:
+			return fmt.Sprintf(" GROUP BY %s WITH CUBE", list(config.GroupBy)), nil
+		}
+
+	case GroupByModeGroupingSets:
+		if dialect == DialectPostgres || dialect == DialectClickHouse || dialect == DialectBigQuery {
+			sets := make([]string, len(config.GroupingSets))
+			for i, set := range config.GroupingSets {
+				sets[i] = "(" + list(set) + ")"
+			}
+			return fmt.Sprintf(" GROUP BY GROUPING SETS (%s)", strings.Join(sets, ", ")), nil
+		}
+
+	default:
+		return "", fmt.Errorf("invalid group_by_mode '%s'", config.GroupByMode)
+	}
+
+	return "", fmt.Errorf("group_by_mode '%s' is not supported by the %s dialect", config.GroupByMode, dialect)
+}
+
+// groupByExpr resolves a GROUP BY entry. An entry naming an axis column that
+// carries a JSON path or a time bucket is grouped by the same expression the
+// axis selects.
+func groupByExpr(config *ChartConfig, column string) string {
+	if column == config.XAxis.Column && config.Options.GapFill != nil {
+		return bucketExpr(config)
+	}
+	if column == config.XAxis.Column && len(config.XAxis.JSONPath) > 0 {
+		return columnExpr(config.XAxis.Column, config.XAxis.JSONPath)
+	}
+	for _, yAxis := range config.YAxis {
+		if column == yAxis.Column && len(yAxis.JSONPath) > 0 && yAxis.Aggregation == "" {
+			return columnExpr(yAxis.Column, yAxis.JSONPath)
+		}
+	}
+	return column
+}
+
+// qualifyTable prefixes a table name with its database, or with config.Schema
+// when no database is given. A database maps to a schema under Postgres.
+// Names that are already qualified, and all names when neither is set, are
+// left as-is.
+func qualifyTable(config *ChartConfig, database, table string) (string, error) {
+	dialect := dialectOf(config)
+	if database != "" {
+		if !supportsDatabase(dialect) {
+			return "", fmt.Errorf("database qualification is not supported by the %s dialect", dialect)
+		}
+		return qualifiedName(dialect, database, table), nil
+	}
+	if config.Schema == "" || strings.Contains(table, ".") {
+		return table, nil
+	}
+	return qualifiedName(dialect, config.Schema, table), nil
+}
+
+// qualifiedName joins a database and table name. BigQuery quotes the whole
+// path, so a database may name a project and dataset:
+// `my-project.sales.orders`.
+func qualifiedName(dialect, database, table string) string {
+	if dialect == DialectBigQuery {
+		return quoteIdent(dialect, database+"."+table)
+	}
+	return quoteIdent(dialect, database) + "." + table
+}
+
+// tenantPredicate renders the tenant restriction against the primary table,
+// qualifying a bare column with the table's alias or name
+func tenantPredicate(config *ChartConfig, b *argBinder) (string, error) {
+	tenant := config.Tenant
+	if !isColumnName(tenant.Column) {
+		return "", fmt.Errorf("invalid tenant column '%s'", tenant.Column)
+	}
+	if tenant.Value == nil {
+		return "", fmt.Errorf("tenant value is required")
+	}
+
+	column := tenant.Column
+	if !strings.Contains(column, ".") {
+		table := config.Tables[0].Alias
+		if table == "" {
+			table = config.Tables[0].Name
+		}
+		column = table + "." + column
+	}
+	return fmt.Sprintf("%s = %s", column, b.bind(tenant.Value)), nil
+}
+
+// isSelfContainedSQL reports whether a raw SQL fragment keeps its parentheses
+// balanced and contains no statement separators or comments, so wrapping it
+// in parentheses cannot change the meaning of the surrounding query
+func isSelfContainedSQL(raw string) bool {
+	depth := 0
+	inString := false
+	for i := 0; i < len(raw); i++ {
+		ch := raw[i]
+		if inString {
+			if ch == '\'' {
+				inString = false
+			}
+			continue
+		}
+		switch ch {
+		case '\'':
+			inString = true
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		case ';':
+			return false
+		case '-', '/':
+			if i+1 < len(raw) && (raw[i:i+2] == "--" || raw[i:i+2] == "/*") {
+				return false
+			}
+		}
+	}
+	return depth == 0 && !inString
+}
+
+// quoteLiteral renders s as a single-quoted SQL string literal
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// arrayArg converts filter values into a typed slice so the driver can encode
+// it as a Postgres array. Mixed-type values are passed through unchanged.
+func arrayArg(values []interface{}) interface{} {
+	switch values[0].(type) {
+	case string:
+		out := make([]string, 0, len(values))
+		for _, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				return values
+			}
+			out = append(out, s)
+		}
+		return out
+	case float64:
+		out := make([]float64, 0, len(values))
+		for _, v := range values {
+			f, ok := v.(float64)
+			if !ok {
+				return values
+			}
+			out = append(out, f)
+		}
+		return out
+	case int:
+		out := make([]int64, 0, len(values))
+		for _, v := range values {
+			n, ok := v.(int)
+			if !ok {
+				return values
+			}
+			out = append(out, int64(n))
+		}
+		return out
+	case int64:
+		out := make([]int64, 0, len(values))
+		for _, v := range values {
+			n, ok := v.(int64)
+			if !ok {
+				return values
+			}
+			out = append(out, n)
+		}
+		return out
+	case bool:
+		out := make([]bool, 0, len(values))
+		for _, v := range values {
+			b, ok := v.(bool)
+			if !ok {
+				return values
+			}
+			out = append(out, b)
 		}
+		return out
+	default:
+		return values
 	}
-	return b.String()
 }
@@ -0,0 +1,152 @@
+package chatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+// whereClause returns the WHERE condition of a built query
+func whereClause(t *testing.T, query string) string {
+	t.Helper()
+	start := strings.Index(query, "WHERE ")
+	end := strings.Index(query, " GROUP BY")
+	if start < 0 || end < start {
+		t.Fatalf("query has no WHERE clause: %s", query)
+	}
+	return query[start+len("WHERE ") : end]
+}
+
+func TestISOperator(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator string
+		value    interface{}
+		dialect  string
+		want     string
+	}{
+		{"IS null", "IS", nil, "", "o.paid IS NULL"},
+		{"IS true", "IS", true, "", "o.paid IS TRUE"},
+		{"IS false", "IS", false, "", "o.paid IS FALSE"},
+		{"IS string true", "IS", "true", "", "o.paid IS TRUE"},
+		{"IS string FALSE", "IS", "FALSE", "", "o.paid IS FALSE"},
+		{"IS NOT null", "IS NOT", nil, "", "o.paid IS NOT NULL"},
+		{"IS NOT true", "IS NOT", true, "", "o.paid IS NOT TRUE"},
+		{"IS NOT false", "IS NOT", false, "", "o.paid IS NOT FALSE"},
+		{"IS NULL", "IS NULL", nil, "", "o.paid IS NULL"},
+		{"IS NOT NULL", "IS NOT NULL", nil, "", "o.paid IS NOT NULL"},
+		{"lowercase is not null", "is not null", nil, "", "o.paid IS NOT NULL"},
+		{"IS true on ClickHouse", "IS", true, DialectClickHouse, "o.paid = true"},
+		{"IS NOT true on ClickHouse", "IS NOT", true, DialectClickHouse, "(o.paid != true OR o.paid IS NULL)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(FilterConfig{Column: "o.paid", Operator: tt.operator, Value: tt.value})
+			config.Dialect = tt.dialect
+			if contains(ValidOperators, tt.operator) {
+				if err := config.Validate(); err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+			}
+
+			query, args, err := BuildChartQuery(config)
+			if err != nil {
+				t.Fatalf("BuildChartQuery() error = %v", err)
+			}
+			if got := whereClause(t, query); got != tt.want {
+				t.Errorf("WHERE %s, want WHERE %s", got, tt.want)
+			}
+			if len(args) != 0 {
+				t.Errorf("args = %v, want none", args)
+			}
+		})
+	}
+}
+
+func TestISOperatorRejectsOtherValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter FilterConfig
+	}{
+		{"IS number", FilterConfig{Column: "o.paid", Operator: "IS", Value: 5}},
+		{"IS string", FilterConfig{Column: "o.paid", Operator: "IS", Value: "yes"}},
+		{"IS NOT number", FilterConfig{Column: "o.paid", Operator: "IS NOT", Value: 0}},
+		{"IS NULL with a value", FilterConfig{Column: "o.paid", Operator: "IS NULL", Value: true}},
+		{"IS NOT NULL with a value", FilterConfig{Column: "o.paid", Operator: "IS NOT NULL", Value: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(tt.filter)
+			if err := config.Validate(); err == nil {
+				t.Error("Validate() accepted the filter")
+			}
+			if _, _, err := BuildChartQuery(config); err == nil {
+				t.Error("BuildChartQuery() accepted the filter")
+			}
+		})
+	}
+
+	config := testConfig(FilterConfig{Column: "o.paid", Operator: "IS NULL", Values: []interface{}{nil}})
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() accepted IS NULL with values")
+	}
+}
+
+func TestAxisCast(t *testing.T) {
+	config := testConfig()
+	config.YAxis[0].Cast = "double precision"
+	query, _, err := BuildChartQuery(config)
+	if err != nil {
+		t.Fatalf("BuildChartQuery() error = %v", err)
+	}
+	if want := "CAST(SUM(o.amount) AS double precision) as y_value_1"; !strings.Contains(query, want) {
+		t.Errorf("query %q does not contain %q", query, want)
+	}
+}
+
+func TestCountRowsOrColumn(t *testing.T) {
+	config := testConfig()
+	config.YAxis = []AxisConfig{{Aggregation: "COUNT"}, {Column: "o.status", Aggregation: "COUNT"}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	query, _, err := BuildChartQuery(config)
+	if err != nil {
+		t.Fatalf("BuildChartQuery() error = %v", err)
+	}
+	// COUNT(*) counts every row; COUNT(o.status) skips rows with a NULL status
+	for _, want := range []string{"COUNT(*) as y_value_1", "COUNT(o.status) as y_value_2"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query %q does not contain %q", query, want)
+		}
+	}
+}
+
+func TestBuildChartQueryRequiresParts(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*ChartConfig)
+		want   string
+	}{
+		{"no tables", func(c *ChartConfig) { c.Tables = nil }, "at least one table is required"},
+		{"empty tables", func(c *ChartConfig) { c.Tables = []TableConfig{} }, "at least one table is required"},
+		{"no y_axis", func(c *ChartConfig) { c.YAxis = nil }, "at least one y-axis is required"},
+		{"no x_axis column", func(c *ChartConfig) { c.XAxis.Column = "" }, "x_axis column is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			tt.mutate(config)
+			// BuildChartQuery does not validate, so it must fail rather than panic
+			query, args, err := BuildChartQuery(config)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("BuildChartQuery() error = %v, want %q", err, tt.want)
+			}
+			if query != "" || args != nil {
+				t.Errorf("BuildChartQuery() = %q, %v, want no query", query, args)
+			}
+		})
+	}
+}
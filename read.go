@@ -0,0 +1,161 @@
+package chatabase
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxConfigBytes is the input size ReadChartConfig and
+// ReadChartConfigs accept by default
+const DefaultMaxConfigBytes = 1 << 20
+
+// ErrConfigTooLarge is returned, wrapped, when the input of ReadChartConfig
+// or ReadChartConfigs exceeds its size limit
+var ErrConfigTooLarge = errors.New("chart config input is too large")
+
+// ReadOptions controls ReadChartConfigWithOptions and ReadChartConfigsWithOptions
+type ReadOptions struct {
+	// MaxBytes fails the read once more than this many bytes arrive, so a
+	// huge request body cannot exhaust memory; <= 0 uses DefaultMaxConfigBytes
+	MaxBytes int64
+}
+
+// ReadChartConfig decodes one JSON chart config from r and validates it like
+// UnmarshalChartConfig, without reading the input into a string first, e.g.
+// straight from an http.Request body. Input over DefaultMaxConfigBytes fails
+// with ErrConfigTooLarge.
+func ReadChartConfig(r io.Reader) (*ChartConfig, error) {
+	return ReadChartConfigWithOptions(r, ReadOptions{})
+}
+
+// ReadChartConfigWithOptions is ReadChartConfig with a size limit
+func ReadChartConfigWithOptions(r io.Reader, opts ReadOptions) (*ChartConfig, error) {
+	dec := json.NewDecoder(newLimitReader(r, opts.MaxBytes))
+
+	var config ChartConfig
+	if err := dec.Decode(&config); err != nil {
+		return nil, readError(err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("failed to unmarshal JSON: unexpected data after the chart config")
+	}
+
+	if err := validateChartConfig(&config); err != nil {
+		return nil, fmt.Errorf("invalid chart configuration: %w", err)
+	}
+	return &config, nil
+}
+
+// ReadChartConfigs decodes and validates several JSON chart configs from r,
+// given either as one array or as a stream of objects, e.g. newline-delimited.
+// Configs are decoded one at a time; input over DefaultMaxConfigBytes in
+// total fails with ErrConfigTooLarge.
+func ReadChartConfigs(r io.Reader) ([]*ChartConfig, error) {
+	return ReadChartConfigsWithOptions(r, ReadOptions{})
+}
+
+// ReadChartConfigsWithOptions is ReadChartConfigs with a size limit
+func ReadChartConfigsWithOptions(r io.Reader, opts ReadOptions) ([]*ChartConfig, error) {
+	in := bufio.NewReader(newLimitReader(r, opts.MaxBytes))
+	array, err := startsArray(in)
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	dec := json.NewDecoder(in)
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, readError(err)
+		}
+	}
+
+	configs := []*ChartConfig{}
+	for dec.More() {
+		var config ChartConfig
+		if err := dec.Decode(&config); err != nil {
+			return nil, fmt.Errorf("config at index %d: %w", len(configs), readError(err))
+		}
+		if err := validateChartConfig(&config); err != nil {
+			return nil, fmt.Errorf("config at index %d: invalid chart configuration: %w", len(configs), err)
+		}
+		configs = append(configs, &config)
+	}
+
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, readError(err)
+		}
+		if dec.More() {
+			return nil, fmt.Errorf("failed to unmarshal JSON: unexpected data after the chart config array")
+		}
+	}
+	return configs, nil
+}
+
+// startsArray reports whether the first non-space byte of in opens a JSON
+// array, without consuming it
+func startsArray(in *bufio.Reader) (bool, error) {
+	for {
+		b, err := in.Peek(1)
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			if _, err := in.ReadByte(); err != nil {
+				return false, err
+			}
+		default:
+			return b[0] == '[', nil
+		}
+	}
+}
+
+// readError wraps a decoding error, keeping ErrConfigTooLarge visible to
+// errors.Is
+func readError(err error) error {
+	if errors.Is(err, ErrConfigTooLarge) {
+		return err
+	}
+	if err == io.EOF {
+		return fmt.Errorf("failed to unmarshal JSON: empty input")
+	}
+	return fmt.Errorf("failed to unmarshal JSON: %w", err)
+}
+
+// limitReader reads from r until more than max bytes have arrived, then
+// fails with ErrConfigTooLarge rather than silently truncating the input
+type limitReader struct {
+	r   io.Reader
+	max int64
+	n   int64
+}
+
+func newLimitReader(r io.Reader, max int64) *limitReader {
+	if max <= 0 {
+		max = DefaultMaxConfigBytes
+	}
+	return &limitReader{r: r, max: max}
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n > l.max {
+		return 0, fmt.Errorf("%w: over %d bytes", ErrConfigTooLarge, l.max)
+	}
+	// Read at most one byte past the limit, enough to tell it was exceeded
+	if remaining := l.max - l.n + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.max {
+		return 0, fmt.Errorf("%w: over %d bytes", ErrConfigTooLarge, l.max)
+	}
+	return n, err
+}
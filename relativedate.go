@@ -0,0 +1,110 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RelativeDate restricts a filter's column to a window relative to the
+// current time, e.g. the last 30 days:
+//
+//	{"column": "created_at", "relative": {"count": 30, "unit": "day"}}
+//
+// The filter takes no operator or value. The count is bound as a parameter.
+type RelativeDate struct {
+	Count     int    `json:"count" yaml:"count" toml:"count"`
+	Unit      string `json:"unit" yaml:"unit" toml:"unit"`                                              // "day", "week", "month"
+	Direction string `json:"direction,omitempty" yaml:"direction,omitempty" toml:"direction,omitempty"` // "last" (default) or "next"
+}
+
+// Directions for RelativeDate
+const (
+	RelativeLast = "last" // column >= now - count units
+	RelativeNext = "next" // column between now and now + count units
+)
+
+var (
+	ValidRelativeUnits      = []string{"day", "week", "month"}
+	ValidRelativeDirections = []string{RelativeLast, RelativeNext}
+)
+
+// validateRelativeFilter validates a relative-date filter
+func validateRelativeFilter(filter *FilterConfig, index int) error {
+	relative := filter.Relative
+	if filter.Operator != "" || filter.Value != nil || len(filter.Values) > 0 || filter.ValueExpr != "" {
+		return fmt.Errorf("relative filter at index %d takes no operator or value", index)
+	}
+	if relative.Count <= 0 {
+		return fmt.Errorf("relative filter count must be positive at index %d, got %d", index, relative.Count)
+	}
+	if !contains(ValidRelativeUnits, relative.Unit) {
+		return fmt.Errorf("invalid relative filter unit '%s' at index %d. Must be one of: %s",
+			relative.Unit, index, strings.Join(ValidRelativeUnits, ", "))
+	}
+	if relative.Direction != "" && !contains(ValidRelativeDirections, relative.Direction) {
+		return fmt.Errorf("invalid relative filter direction '%s' at index %d. Must be one of: %s",
+			relative.Direction, index, strings.Join(ValidRelativeDirections, ", "))
+	}
+	return nil
+}
+
+// relativeDateExpr renders a relative-date predicate in the binder's dialect:
+//
+//	postgres:   created_at >= now() - make_interval(days => $1)
+//	mysql:      created_at >= DATE_SUB(NOW(), INTERVAL ? DAY)
+//	sqlite:     created_at >= datetime('now', ?) with '-30 days'
+//	clickhouse: created_at >= subtractDays(now(), ?)
+//	bigquery:   created_at >= TIMESTAMP(DATETIME_SUB(CURRENT_DATETIME(), INTERVAL @p1 DAY))
+func relativeDateExpr(b *argBinder, column string, relative RelativeDate) string {
+	var now, bound string
+	switch b.dialect {
+	case DialectMySQL:
+		now = "NOW()"
+		function := "DATE_SUB"
+		if relative.Direction == RelativeNext {
+			function = "DATE_ADD"
+		}
+		bound = fmt.Sprintf("%s(NOW(), INTERVAL %s %s)", function, b.bind(relative.Count), strings.ToUpper(relative.Unit))
+	case DialectClickHouse:
+		now = "now()"
+		function := "subtract"
+		if relative.Direction == RelativeNext {
+			function = "add"
+		}
+		unit := strings.ToUpper(relative.Unit[:1]) + relative.Unit[1:]
+		bound = fmt.Sprintf("%s%ss(now(), %s)", function, unit, b.bind(relative.Count))
+	case DialectBigQuery:
+		// TIMESTAMP_SUB stops at days, so weeks and months are subtracted
+		// from the UTC datetime and converted back
+		now = "CURRENT_TIMESTAMP()"
+		function := "DATETIME_SUB"
+		if relative.Direction == RelativeNext {
+			function = "DATETIME_ADD"
+		}
+		bound = fmt.Sprintf("TIMESTAMP(%s(CURRENT_DATETIME(), INTERVAL %s %s))", function, b.bind(relative.Count), strings.ToUpper(relative.Unit))
+	case DialectSQLite:
+		// SQLite modifiers have no weeks; its 'now' is UTC
+		count, unit := relative.Count, relative.Unit
+		if unit == "week" {
+			count, unit = count*7, "day"
+		}
+		sign := "-"
+		if relative.Direction == RelativeNext {
+			sign = "+"
+		}
+		now = "datetime('now')"
+		bound = fmt.Sprintf("datetime('now', %s)", b.bind(fmt.Sprintf("%s%d %ss", sign, count, unit)))
+	default:
+		sign := "-"
+		if relative.Direction == RelativeNext {
+			sign = "+"
+		}
+		now = "now()"
+		bound = fmt.Sprintf("now() %s make_interval(%ss => %s)", sign, relative.Unit, b.bind(relative.Count))
+	}
+
+	if relative.Direction == RelativeNext {
+		return fmt.Sprintf("%s BETWEEN %s AND %s", column, now, bound)
+	}
+	return fmt.Sprintf("%s >= %s", column, bound)
+}
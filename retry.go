@@ -0,0 +1,101 @@
+package chatabase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// RetryPolicy controls how ExecuteChartWithRetry retries transient failures
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry; it doubles after
+	// each retry up to MaxBackoff (when positive)
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Options are passed to every attempt; may be nil
+	Options *ExecuteOptions
+}
+
+// DefaultRetryPolicy tries three times, waiting 100ms then 200ms
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// transientSQLStates are the SQLSTATE codes worth retrying
+var transientSQLStates = []string{
+	"40001", // serialization_failure
+	"40P01", // deadlock_detected
+	"57P01", // admin_shutdown, e.g. a replica failing over
+	"57P02", // crash_shutdown
+	"57P03", // cannot_connect_now
+}
+
+// IsTransientError reports whether a chart query error is worth retrying:
+// serialization failures, deadlocks, server shutdowns and connection errors
+// (SQLSTATE class 08). Errors are classified by their SQLSTATE code, as
+// reported by pgx and lib/pq errors, never by message.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var coded interface{ SQLState() string }
+	if errors.As(err, &coded) {
+		code := coded.SQLState()
+		return strings.HasPrefix(code, "08") || contains(transientSQLStates, code)
+	}
+
+	var connectErr *pgconn.ConnectError
+	return errors.As(err, &connectErr)
+}
+
+// ExecuteChartWithRetry is ExecuteChart that retries transient errors
+// according to policy
+func ExecuteChartWithRetry(db *sqlx.DB, config *ChartConfig, policy RetryPolicy) ([]ChartDataRow, error) {
+	return ExecuteChartWithRetryContext(context.Background(), db, config, policy)
+}
+
+// ExecuteChartWithRetryContext is ExecuteChartWithRetry with a context for
+// cancellation and timeouts. Cancelling ctx stops further attempts.
+func ExecuteChartWithRetryContext(ctx context.Context, db *sqlx.DB, config *ChartConfig, policy RetryPolicy) ([]ChartDataRow, error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := policy.InitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var rows []ChartDataRow
+		rows, err = ExecuteChartContext(ctx, db, config, policy.Options)
+		if err == nil {
+			return rows, nil
+		}
+		if !IsTransientError(err) || attempt == attempts {
+			return nil, fmt.Errorf("chart query failed after %d attempt(s): %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("chart query failed after %d attempt(s): %w", attempt, errors.Join(err, ctx.Err()))
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,211 @@
+package chatabase
+
+// ChartConfigSchema returns a JSON Schema (draft 2020-12) document describing
+// ChartConfig, e.g. to generate a config form. The enums are built from the
+// lists validation uses, and only fields every valid config sets are
+// required, so the schema accepts whatever ValidateAndNormalizeConfig does.
+// It is looser than validation: cross-field rules, such as BETWEEN needing
+// two values or a filter needing an operator unless it is raw or relative,
+// are only enforced by validation.
+func ChartConfigSchema() map[string]interface{} {
+	filter := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"column": stringSchema("Column or expression to filter on"),
+			"operator": map[string]interface{}{
+				"description": "Comparison operator; also EXISTS / NOT EXISTS and '<op> ANY' / '<op> ALL' with a subquery. Raw and relative filters take none.",
+				"type":        "string",
+				"enum":        append([]string{""}, filterOperators()...),
+			},
+			"value":         map[string]interface{}{"description": "Value for single-value operators"},
+			"values":        map[string]interface{}{"type": "array", "description": "Values for IN, BETWEEN and the array operators"},
+			"columns":       stringArraySchema("Columns of a multi-column IN"),
+			"tuples":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "array"}},
+			"json_path":     stringArraySchema("Keys to extract from a jsonb column"),
+			"subquery":      stringSchema("Subquery for EXISTS, ANY and ALL"),
+			"subquery_args": map[string]interface{}{"type": "array"},
+			"relative": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"count", "unit"},
+				"properties": map[string]interface{}{
+					"count":     map[string]interface{}{"type": "integer", "minimum": 1},
+					"unit":      enumSchema(ValidRelativeUnits),
+					"direction": optionalEnumSchema(ValidRelativeDirections),
+				},
+			},
+			"value_expr": stringSchema("Trusted SQL expression compared in place of value, e.g. now() - interval '7 days'"),
+			"raw":        stringSchema("Raw SQL predicate with '?' placeholders"),
+			"raw_values": map[string]interface{}{"type": "array"},
+		},
+	}
+
+	axis := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"column":        stringSchema("Column or expression; empty counts rows with COUNT"),
+			"label":         stringSchema("Human-readable label"),
+			"aggregation":   optionalEnumSchema(ValidAggregations),
+			"percentile":    map[string]interface{}{"type": "number", "exclusiveMinimum": 0, "maximum": 1},
+			"delimiter":     stringSchema("Separator for the STRING_AGG aggregation"),
+			"data_type":     optionalEnumSchema([]string{DataTypeNumeric, DataTypeDatetime, DataTypeString, DataTypeBoolean}),
+			"format":        stringSchema("Display format, e.g. currency, percentage, date"),
+			"alias":         stringSchema("Result column name"),
+			"json_path":     stringArraySchema("Keys to extract from a jsonb column"),
+			"null_as":       map[string]interface{}{"description": "Default for NULL values"},
+			"axis_side":     optionalEnumSchema(ValidAxisSides),
+			"cast":          stringSchema("SQL type to cast the value to, e.g. double precision"),
+			"numerator":     map[string]interface{}{"$ref": "#/$defs/operand"},
+			"denominator":   map[string]interface{}{"$ref": "#/$defs/operand"},
+			"operation":     optionalEnumSchema(ValidOperations),
+			"series_filter": arraySchema(map[string]interface{}{"$ref": "#/$defs/filter"}),
+		},
+	}
+
+	operand := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"column":      stringSchema("Column or expression; empty counts rows with COUNT"),
+			"aggregation": optionalEnumSchema([]string{"SUM", "COUNT", "AVG", "MIN", "MAX"}),
+		},
+	}
+
+	join := map[string]interface{}{
+		"type":     "object",
+		"required": []string{"table", "condition"},
+		"properties": map[string]interface{}{
+			"table":     stringSchema("Joined table"),
+			"alias":     stringSchema("Table alias"),
+			"database":  stringSchema("Database (Postgres schema) the table lives in"),
+			"type":      optionalEnumSchema(ValidJoinTypes),
+			"condition": stringSchema("Join condition, e.g. users.id = orders.user_id"),
+		},
+	}
+
+	table := map[string]interface{}{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": map[string]interface{}{
+			"name":     stringSchema("Table name"),
+			"alias":    stringSchema("Table alias"),
+			"database": stringSchema("Database (Postgres schema) the table lives in"),
+			"joins":    arraySchema(map[string]interface{}{"$ref": "#/$defs/join"}),
+			"sample": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"method", "percent"},
+				"properties": map[string]interface{}{
+					"method":  enumSchema(ValidSampleMethods),
+					"percent": map[string]interface{}{"type": "number", "exclusiveMinimum": 0, "maximum": 100},
+				},
+			},
+		},
+	}
+
+	order := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"column":      stringSchema("Column or alias to order by; empty counts rows with COUNT"),
+			"direction":   optionalEnumSchema(ValidOrderDirections),
+			"aggregation": optionalEnumSchema(orderAggregations),
+		},
+	}
+
+	options := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"width":                   map[string]interface{}{"type": "integer"},
+			"height":                  map[string]interface{}{"type": "integer"},
+			"theme":                   map[string]interface{}{"type": "string"},
+			"stacked":                 map[string]interface{}{"type": "boolean"},
+			"show_legend":             map[string]interface{}{"type": "boolean"},
+			"show_grid":               map[string]interface{}{"type": "boolean"},
+			"date_format":             map[string]interface{}{"type": "string"},
+			"time_interval":           optionalEnumSchema(ValidTimeIntervals),
+			"timezone":                stringSchema("IANA zone the time buckets are cut in, e.g. America/New_York; requires gap_fill"),
+			"fiscal_year_start_month": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 12},
+			"gap_fill": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"start": map[string]interface{}{},
+					"end":   map[string]interface{}{},
+					"step":  stringSchema("Step between buckets, e.g. 1 day"),
+				},
+			},
+			"colors":        stringArraySchema("Series colors, by position"),
+			"series_colors": map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}},
+			"annotate_sql":  map[string]interface{}{"type": "boolean"},
+		},
+	}
+
+	return map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"title":    "ChartConfig",
+		"type":     "object",
+		"required": []string{"chart_type", "tables", "x_axis", "y_axis"},
+		"properties": map[string]interface{}{
+			"schema_version":      map[string]interface{}{"type": "integer", "maximum": CurrentSchemaVersion},
+			"chart_type":          enumSchema(ValidChartTypes),
+			"title":               stringSchema("Chart title"),
+			"description":         stringSchema("Chart description"),
+			"tables":              arraySchema(map[string]interface{}{"$ref": "#/$defs/table"}),
+			"schema":              stringSchema("Schema qualifying every table"),
+			"joins":               arraySchema(map[string]interface{}{"$ref": "#/$defs/join"}),
+			"dialect":             optionalEnumSchema(ValidDialects),
+			"x_axis":              map[string]interface{}{"$ref": "#/$defs/axis"},
+			"y_axis":              arraySchema(map[string]interface{}{"$ref": "#/$defs/axis"}),
+			"group_by":            stringArraySchema("Columns or expressions to group by"),
+			"group_by_mode":       optionalEnumSchema(ValidGroupByModes),
+			"grouping_sets":       map[string]interface{}{"type": "array", "items": stringArraySchema("One grouping set")},
+			"filters":             arraySchema(map[string]interface{}{"$ref": "#/$defs/filter"}),
+			"having":              arraySchema(map[string]interface{}{"$ref": "#/$defs/filter"}),
+			"options":             options,
+			"distinct_on":         stringArraySchema("Keep the first row per distinct value; order_by must lead with these (Postgres only)"),
+			"limit":               map[string]interface{}{"type": "integer", "minimum": 0},
+			"order_by":            arraySchema(map[string]interface{}{"$ref": "#/$defs/order"}),
+			"with_ties":           map[string]interface{}{"type": "boolean", "description": "Keep rows tied with the last row of limit (Postgres only)"},
+			"null_handling":       optionalEnumSchema(ValidNullHandling),
+			"include_total_count": map[string]interface{}{"type": "boolean", "description": "Add a total_count column with the row count before limit"},
+		},
+		"$defs": map[string]interface{}{
+			"table":   table,
+			"join":    join,
+			"axis":    axis,
+			"operand": operand,
+			"filter":  filter,
+			"order":   order,
+		},
+	}
+}
+
+// filterOperators lists every operator a filter accepts: ValidOperators,
+// EXISTS / NOT EXISTS and the ANY / ALL subquery comparisons
+func filterOperators() []string {
+	operators := append(append([]string{}, ValidOperators...), "EXISTS", "NOT EXISTS")
+	for _, quantifier := range []string{"ANY", "ALL"} {
+		for _, comparison := range subqueryComparisons {
+			operators = append(operators, comparison+" "+quantifier)
+		}
+	}
+	return operators
+}
+
+func stringSchema(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+func stringArraySchema(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": description}
+}
+
+func arraySchema(items map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "array", "items": items}
+}
+
+func enumSchema(values []string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "enum": values}
+}
+
+// optionalEnumSchema also allows "", which selects the default and is what
+// MarshalChartConfig writes for an unset field
+func optionalEnumSchema(values []string) map[string]interface{} {
+	return enumSchema(append([]string{""}, values...))
+}
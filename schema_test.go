@@ -0,0 +1,142 @@
+package chatabase
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// checkSchema checks value against the type, enum, required, properties and
+// items keywords of a JSON Schema node, resolving $ref against root
+func checkSchema(root, node map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := node["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		return checkSchema(root, root["$defs"].(map[string]interface{})[name].(map[string]interface{}), value, path)
+	}
+
+	if enum, ok := node["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if allowed == value {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not in the enum", path, value)
+		}
+	}
+
+	switch node["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an object", path, value)
+		}
+		required, _ := node["required"].([]interface{})
+		for _, key := range required {
+			if _, ok := object[key.(string)]; !ok {
+				return fmt.Errorf("%s: required key %v is missing", path, key)
+			}
+		}
+		properties, _ := node["properties"].(map[string]interface{})
+		for key, child := range object {
+			if schema, ok := properties[key].(map[string]interface{}); ok && child != nil {
+				if err := checkSchema(root, schema, child, path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", path, value)
+		}
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			for i, item := range array {
+				if err := checkSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: %v is not a string", path, value)
+		}
+	}
+	return nil
+}
+
+// TestChartConfigSchemaAcceptsValidConfigs checks every config validation
+// accepts, from the golden inputs and a few shapes they lack, against the schema
+func TestChartConfigSchemaAcceptsValidConfigs(t *testing.T) {
+	data, err := json.Marshal(ChartConfigSchema())
+	if err != nil {
+		t.Fatalf("failed to encode schema: %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("failed to decode schema: %v", err)
+	}
+
+	configs := map[string]*ChartConfig{
+		"raw filter":      testConfig(FilterConfig{Raw: "o.amount > ?", RawValues: []interface{}{10}}),
+		"relative filter": testConfig(FilterConfig{Column: "o.created_at", Relative: &RelativeDate{Count: 7, Unit: "day"}}),
+		"= ANY subquery":  testConfig(FilterConfig{Column: "o.region", Operator: "= ANY", Subquery: "SELECT region FROM regions"}),
+		"> ALL subquery":  testConfig(FilterConfig{Column: "o.amount", Operator: "> ALL", Subquery: "SELECT amount FROM limits"}),
+		"NOT EXISTS":      testConfig(FilterConfig{Operator: "NOT EXISTS", Subquery: "SELECT 1 FROM refunds r WHERE r.order_id = o.id"}),
+		"IS NOT NULL":     testConfig(FilterConfig{Column: "o.status", Operator: "IS NOT NULL"}),
+	}
+	unset := testConfig()
+	unset.Tables[0].Joins = []JoinConfig{{Table: "users", Alias: "u", Condition: "u.id = o.user_id"}}
+	unset.OrderBy = []OrderConfig{{Column: "y_value_1"}}
+	configs["unset enums"] = unset
+	counted := testConfig()
+	counted.YAxis = []AxisConfig{{Aggregation: "COUNT"}}
+	counted.OrderBy = []OrderConfig{{Aggregation: "COUNT", Direction: "DESC"}}
+	configs["COUNT rows"] = counted
+	derived := testConfig()
+	derived.YAxis = []AxisConfig{{Numerator: &Operand{Column: "o.amount", Aggregation: "SUM"}, Denominator: &Operand{Aggregation: "COUNT"}}}
+	configs["derived axis"] = derived
+
+	inputs, err := filepath.Glob(filepath.Join("testdata", "golden", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range inputs {
+		data, err := os.ReadFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var config ChartConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			t.Fatalf("%s: %v", input, err)
+		}
+		configs[filepath.Base(input)] = &config
+	}
+
+	checked := 0
+	for name, config := range configs {
+		if err := validateChartConfig(config); err != nil {
+			continue
+		}
+		checked++
+
+		data, err := json.Marshal(config)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var value map[string]interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := checkSchema(schema, schema, value, "config"); err != nil {
+			t.Errorf("%s is valid but the schema rejects it: %v", name, err)
+		}
+	}
+	if checked < len(configs)/2 {
+		t.Errorf("only %d of %d configs validate", checked, len(configs))
+	}
+}
@@ -0,0 +1,117 @@
+package chatabase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SchemaCache memoizes table and column introspection so UIs can look up
+// schema information on every keystroke without re-running the catalog
+// queries. Entries expire after the TTL; a TTL <= 0 keeps them until
+// Invalidate is called. A SchemaCache is safe for concurrent use.
+type SchemaCache struct {
+	db  *sqlx.DB
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	tables  map[string]cachedTables  // keyed by schema
+	columns map[string]cachedColumns // keyed by schema + "." + table
+}
+
+type cachedTables struct {
+	tables    []string
+	expiresAt time.Time
+}
+
+type cachedColumns struct {
+	columns   []ColumnInfo
+	expiresAt time.Time
+}
+
+// NewSchemaCache creates a SchemaCache reading from db
+func NewSchemaCache(db *sqlx.DB, ttl time.Duration) *SchemaCache {
+	return &SchemaCache{
+		db:      db,
+		ttl:     ttl,
+		tables:  make(map[string]cachedTables),
+		columns: make(map[string]cachedColumns),
+	}
+}
+
+// GetTables returns the tables in a schema, loading them on a cache miss
+func (c *SchemaCache) GetTables(schemaName string) ([]string, error) {
+	return c.GetTablesContext(context.Background(), schemaName)
+}
+
+// GetTablesContext is GetTables with a context for cancellation and timeouts
+func (c *SchemaCache) GetTablesContext(ctx context.Context, schemaName string) ([]string, error) {
+	c.mu.RLock()
+	entry, ok := c.tables[schemaName]
+	c.mu.RUnlock()
+	if ok && c.fresh(entry.expiresAt) {
+		return append([]string(nil), entry.tables...), nil
+	}
+
+	tables, err := GetSchemaTablesContext(ctx, c.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.tables[schemaName] = cachedTables{tables: tables, expiresAt: c.expiry()}
+	c.mu.Unlock()
+
+	return append([]string(nil), tables...), nil
+}
+
+// GetColumnInfo returns the columns of a table, loading them on a cache miss
+func (c *SchemaCache) GetColumnInfo(schemaName, tableName string) ([]ColumnInfo, error) {
+	return c.GetColumnInfoContext(context.Background(), schemaName, tableName)
+}
+
+// GetColumnInfoContext is GetColumnInfo with a context for cancellation and timeouts
+func (c *SchemaCache) GetColumnInfoContext(ctx context.Context, schemaName, tableName string) ([]ColumnInfo, error) {
+	key := schemaName + "." + tableName
+
+	c.mu.RLock()
+	entry, ok := c.columns[key]
+	c.mu.RUnlock()
+	if ok && c.fresh(entry.expiresAt) {
+		return append([]ColumnInfo(nil), entry.columns...), nil
+	}
+
+	columns, err := GetSchemaColumnInfoContext(ctx, c.db, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.columns[key] = cachedColumns{columns: columns, expiresAt: c.expiry()}
+	c.mu.Unlock()
+
+	return append([]ColumnInfo(nil), columns...), nil
+}
+
+// Invalidate drops every cached entry, e.g. after running a migration
+func (c *SchemaCache) Invalidate() {
+	c.mu.Lock()
+	c.tables = make(map[string]cachedTables)
+	c.columns = make(map[string]cachedColumns)
+	c.mu.Unlock()
+}
+
+// expiry returns the expiration time for an entry stored now
+func (c *SchemaCache) expiry() time.Time {
+	if c.ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(c.ttl)
+}
+
+// fresh reports whether an entry with the given expiration is still valid
+func (c *SchemaCache) fresh(expiresAt time.Time) bool {
+	return expiresAt.IsZero() || time.Now().Before(expiresAt)
+}
@@ -0,0 +1,374 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateAgainstSchema checks that every column a config references exists
+// in snapshot: the axes, filters, group-by, having, distinct-on and order-by
+// expressions and the join conditions. Qualified references must name a table
+// or alias of the config that has the column; bare references must belong to
+// one of the config's tables. Y-axis aliases and x_value are accepted where
+// SQL or the builder resolves them. Raw filters, value expressions and subqueries are not
+// inspected.
+//
+// Tables qualified with another database (Postgres schema) than the
+// snapshot's cannot be checked; their columns are assumed to exist.
+func ValidateAgainstSchema(config *ChartConfig, snapshot *SchemaSnapshot) error {
+	scope, err := newSchemaScope(config, snapshot)
+	if err != nil {
+		return err
+	}
+
+	return visitColumnRefs(config, func(where string, ref ColumnRef, allowAliases bool) error {
+		if allowAliases && ref.Table == "" && scope.aliases[ref.Column] {
+			return nil
+		}
+		if err := scope.check(ref); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		return nil
+	})
+}
+
+// visitColumnRefs calls visit with every column the config's expressions
+// reference: the axes and their series filters, join conditions, filters,
+// group-by, having, distinct-on and order-by. where names the field, e.g.
+// "filters[2]", and allowAliases reports whether x_value and the Y-axis
+// aliases may stand for a column there. Raw filters, value expressions and subqueries are skipped.
+func visitColumnRefs(config *ChartConfig, visit func(where string, ref ColumnRef, allowAliases bool) error) error {
+	check := func(where, expr string, allowAliases bool) error {
+		refs, err := expressionRefs(expr)
+		if err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		for _, ref := range refs {
+			if err := visit(where, ref, allowAliases); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	checkJoin := func(where string, join JoinConfig) error {
+		refs, err := ParseJoinCondition(join.Condition)
+		if err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		for _, ref := range refs {
+			if err := visit(where, ref, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := check("x_axis", config.XAxis.Column, false); err != nil {
+		return err
+	}
+	for i, yAxis := range config.YAxis {
+		where := fmt.Sprintf("y_axis[%d]", i)
+		if err := check(where, yAxis.Column, false); err != nil {
+			return err
+		}
+		for _, operand := range []*Operand{yAxis.Numerator, yAxis.Denominator} {
+			if operand == nil {
+				continue
+			}
+			if err := check(where, operand.Column, false); err != nil {
+				return err
+			}
+		}
+		for j, filter := range yAxis.SeriesFilter {
+			where := fmt.Sprintf("y_axis[%d].series_filter[%d]", i, j)
+			if err := check(where, filter.Column, true); err != nil {
+				return err
+			}
+			for _, column := range filter.Columns {
+				if err := check(where, column, false); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for i, table := range config.Tables {
+		for j, join := range table.Joins {
+			if err := checkJoin(fmt.Sprintf("tables[%d].joins[%d].condition", i, j), join); err != nil {
+				return err
+			}
+		}
+	}
+	for i, join := range config.Joins {
+		if err := checkJoin(fmt.Sprintf("joins[%d].condition", i), join); err != nil {
+			return err
+		}
+	}
+	for i, filter := range config.Filters {
+		where := fmt.Sprintf("filters[%d]", i)
+		if err := check(where, filter.Column, true); err != nil {
+			return err
+		}
+		for _, column := range filter.Columns {
+			if err := check(where, column, false); err != nil {
+				return err
+			}
+		}
+	}
+	for i, column := range config.GroupBy {
+		if err := check(fmt.Sprintf("group_by[%d]", i), column, true); err != nil {
+			return err
+		}
+	}
+	for i, filter := range config.Having {
+		if err := check(fmt.Sprintf("having[%d]", i), filter.Column, true); err != nil {
+			return err
+		}
+	}
+	for i, column := range config.DistinctOn {
+		if err := check(fmt.Sprintf("distinct_on[%d]", i), column, false); err != nil {
+			return err
+		}
+	}
+	for i, order := range config.OrderBy {
+		if err := check(fmt.Sprintf("order_by[%d]", i), order.Column, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResolveColumn finds which of the config's tables owns a bare column and
+// returns it qualified with that table's alias, or name when it has none:
+// "email" -> u.email. It fails when no table or more than one has the column,
+// or when a table is not in the snapshot. Qualified columns are returned
+// after checking they exist.
+func (s *SchemaSnapshot) ResolveColumn(config *ChartConfig, column string) (ColumnRef, error) {
+	scope, err := newSchemaScope(config, s)
+	if err != nil {
+		return ColumnRef{}, err
+	}
+
+	table, name, qualified := strings.Cut(column, ".")
+	if qualified {
+		ref := ColumnRef{Table: table, Column: name}
+		if err := scope.check(ref); err != nil {
+			return ColumnRef{}, err
+		}
+		return ref, nil
+	}
+	return scope.resolve(column, s.Schema)
+}
+
+// QualifyColumns returns a copy of config in which every bare column name is
+// qualified with the alias, or name, of the one table that has it, as
+// ResolveColumn does: with users u joined to orders o on u.id = o.user_id,
+// "email" becomes "u.email". It fails when a bare column is in none or
+// several of the tables, e.g. an "id" both have. Only plain names are
+// rewritten: expressions such as DATE_TRUNC('month', created_at), join
+// conditions, raw filters and subqueries are left as written, as are x_value
+// and the Y-axis aliases where they stand for a column. config is not
+// modified.
+func QualifyColumns(config *ChartConfig, snapshot *SchemaSnapshot) (*ChartConfig, error) {
+	scope, err := newSchemaScope(config, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	qualified := config.Clone()
+	qualify := func(where string, column *string, allowAliases bool) error {
+		if !isIdentifier(*column) || sqlKeywords[strings.ToLower(*column)] || allowAliases && scope.aliases[*column] {
+			return nil
+		}
+		ref, err := scope.resolve(*column, snapshot.Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		*column = ref.String()
+		return nil
+	}
+
+	if err := qualify("x_axis", &qualified.XAxis.Column, false); err != nil {
+		return nil, err
+	}
+	for i := range qualified.YAxis {
+		yAxis := &qualified.YAxis[i]
+		where := fmt.Sprintf("y_axis[%d]", i)
+		if err := qualify(where, &yAxis.Column, false); err != nil {
+			return nil, err
+		}
+		for _, operand := range []*Operand{yAxis.Numerator, yAxis.Denominator} {
+			if operand == nil {
+				continue
+			}
+			if err := qualify(where, &operand.Column, false); err != nil {
+				return nil, err
+			}
+		}
+		for j := range yAxis.SeriesFilter {
+			filter := &yAxis.SeriesFilter[j]
+			where := fmt.Sprintf("y_axis[%d].series_filter[%d]", i, j)
+			if err := qualify(where, &filter.Column, true); err != nil {
+				return nil, err
+			}
+			for k := range filter.Columns {
+				if err := qualify(where, &filter.Columns[k], false); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for i := range qualified.Filters {
+		filter := &qualified.Filters[i]
+		where := fmt.Sprintf("filters[%d]", i)
+		if err := qualify(where, &filter.Column, true); err != nil {
+			return nil, err
+		}
+		for j := range filter.Columns {
+			if err := qualify(where, &filter.Columns[j], false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i := range qualified.GroupBy {
+		if err := qualify(fmt.Sprintf("group_by[%d]", i), &qualified.GroupBy[i], true); err != nil {
+			return nil, err
+		}
+	}
+	for i, set := range qualified.GroupingSets {
+		for j := range set {
+			if err := qualify(fmt.Sprintf("grouping_sets[%d][%d]", i, j), &set[j], true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i := range qualified.Having {
+		if err := qualify(fmt.Sprintf("having[%d]", i), &qualified.Having[i].Column, true); err != nil {
+			return nil, err
+		}
+	}
+	for i := range qualified.DistinctOn {
+		if err := qualify(fmt.Sprintf("distinct_on[%d]", i), &qualified.DistinctOn[i], false); err != nil {
+			return nil, err
+		}
+	}
+	for i := range qualified.OrderBy {
+		if err := qualify(fmt.Sprintf("order_by[%d]", i), &qualified.OrderBy[i].Column, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return qualified, nil
+}
+
+// schemaScope holds the tables a config can reference, keyed by alias and
+// name, with their columns from a snapshot
+type schemaScope struct {
+	tables     map[string]map[string]bool // alias or name -> columns; nil when unchecked
+	order      []string                   // table keys in config order, for error messages
+	unverified bool                       // a table could not be checked
+	aliases    map[string]bool            // x_value and the Y-axis aliases
+}
+
+func newSchemaScope(config *ChartConfig, snapshot *SchemaSnapshot) (*schemaScope, error) {
+	known := make(map[string]map[string]bool, len(snapshot.Tables))
+	for _, table := range snapshot.Tables {
+		columns := make(map[string]bool, len(table.Columns))
+		for _, column := range table.Columns {
+			columns[column.Name] = true
+		}
+		known[table.Name] = columns
+	}
+
+	scope := &schemaScope{
+		tables:  make(map[string]map[string]bool),
+		aliases: map[string]bool{"x_value": true},
+	}
+	for i, yAxis := range config.YAxis {
+		scope.aliases[yAlias(i, yAxis)] = true
+	}
+
+	add := func(database, name, alias string) error {
+		key := alias
+		if key == "" {
+			key = name
+		}
+		scope.order = append(scope.order, key)
+
+		if database != "" && database != snapshot.Schema {
+			scope.tables[key] = nil
+			scope.unverified = true
+			return nil
+		}
+		bare := name[strings.LastIndex(name, ".")+1:]
+		columns, ok := known[bare]
+		if !ok {
+			return fmt.Errorf("table '%s' does not exist in schema '%s'", name, snapshot.Schema)
+		}
+		scope.tables[key] = columns
+		return nil
+	}
+	for _, table := range config.Tables {
+		if err := add(table.Database, table.Name, table.Alias); err != nil {
+			return nil, err
+		}
+		for _, join := range table.Joins {
+			if err := add(join.Database, join.Table, join.Alias); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, join := range config.Joins {
+		if err := add(join.Database, join.Table, join.Alias); err != nil {
+			return nil, err
+		}
+	}
+
+	return scope, nil
+}
+
+// resolve qualifies a bare column with the one table that has it
+func (s *schemaScope) resolve(column, schema string) (ColumnRef, error) {
+	if s.unverified {
+		return ColumnRef{}, fmt.Errorf("cannot resolve column '%s': not every table is in schema '%s'", column, schema)
+	}
+
+	var owners []string
+	for _, key := range s.order {
+		if s.tables[key][column] {
+			owners = append(owners, key)
+		}
+	}
+	switch len(owners) {
+	case 0:
+		return ColumnRef{}, fmt.Errorf("column '%s' does not exist in any of the tables %s", column, strings.Join(s.order, ", "))
+	case 1:
+		return ColumnRef{Table: owners[0], Column: column}, nil
+	default:
+		return ColumnRef{}, fmt.Errorf("column '%s' is ambiguous: it exists in %s", column, strings.Join(owners, ", "))
+	}
+}
+
+// check returns an error when ref does not resolve to a known column
+func (s *schemaScope) check(ref ColumnRef) error {
+	if ref.Table != "" {
+		columns, ok := s.tables[ref.Table]
+		if !ok {
+			return fmt.Errorf("column '%s' references unknown table or alias '%s'", ref, ref.Table)
+		}
+		if columns != nil && !columns[ref.Column] {
+			return fmt.Errorf("column '%s' does not exist: table '%s' has no column '%s'", ref, ref.Table, ref.Column)
+		}
+		return nil
+	}
+
+	if s.unverified {
+		return nil
+	}
+	for _, key := range s.order {
+		if s.tables[key][ref.Column] {
+			return nil
+		}
+	}
+	return fmt.Errorf("column '%s' does not exist in any of the tables %s", ref.Column, strings.Join(s.order, ", "))
+}
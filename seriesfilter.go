@@ -0,0 +1,71 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateSeriesFilter checks the series_filter of the Y axis at index. Its
+// filters take the same forms as config.Filters; the axis must be a plain
+// aggregate for the filter to have rows to restrict.
+func validateSeriesFilter(config *ChartConfig, axis AxisConfig, index int) error {
+	if axis.Numerator != nil || axis.Denominator != nil {
+		return fmt.Errorf("series_filter for y_axis at index %d cannot be combined with a derived series", index)
+	}
+	if axis.Aggregation == "" {
+		return fmt.Errorf("series_filter for y_axis at index %d requires an aggregation", index)
+	}
+	if axis.Aggregation == AggregationPercent {
+		return fmt.Errorf("series_filter for y_axis at index %d cannot be combined with the %s aggregation", index, AggregationPercent)
+	}
+	for i, filter := range axis.SeriesFilter {
+		if err := validateWhereFilter(config, &filter, i); err != nil {
+			return fmt.Errorf("series_filter for y_axis at index %d: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// filteredAggregateExpr aggregates only the rows the series filter of the Y
+// axis at index keeps. Postgres appends a FILTER clause:
+//
+//	SUM(amount) FILTER (WHERE status = $1)
+//
+// The other dialects aggregate a CASE that is NULL for the other rows, which
+// every aggregate skips: SUM(CASE WHEN status = ? THEN amount END), and
+// COUNT(CASE WHEN status = ? THEN 1 END) for a row count. The conditions are
+// bound in the order they appear in the SQL. With b nil the filters are
+// described rather than bound, as for ChartConfig.String.
+func filteredAggregateExpr(config *ChartConfig, b *argBinder, index int, axis AxisConfig) (string, error) {
+	if axis.Aggregation == "" || axis.Aggregation == AggregationPercent {
+		return "", fmt.Errorf("series_filter requires an aggregation other than %s", AggregationPercent)
+	}
+	column := columnExpr(axis.Column, axis.JSONPath)
+
+	if b == nil {
+		conditions := make([]string, len(axis.SeriesFilter))
+		for i, filter := range axis.SeriesFilter {
+			conditions[i] = describeFilter(filter)
+		}
+		return fmt.Sprintf("%s FILTER (WHERE %s)", aggregateExpr(nil, axis, column), strings.Join(conditions, " AND ")), nil
+	}
+
+	field := fmt.Sprintf("y_axis[%d].series_filter", index)
+	if b.dialect == DialectPostgres {
+		aggregate := aggregateExpr(b, axis, column)
+		condition, err := buildWhere(config, b, field, axis.SeriesFilter)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", field, err)
+		}
+		return fmt.Sprintf("%s FILTER (WHERE %s)", aggregate, condition), nil
+	}
+
+	condition, err := buildWhere(config, b, field, axis.SeriesFilter)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", field, err)
+	}
+	if column == "" || column == "*" {
+		column = "1"
+	}
+	return aggregateExpr(b, axis, fmt.Sprintf("CASE WHEN %s THEN %s END", condition, column)), nil
+}
@@ -0,0 +1,35 @@
+package chatabase
+
+// ChartTypeSuggestions maps the data types of an X and a Y column, as
+// classified by ClassifyDataType, to the chart type SuggestChartType returns.
+// The Y type is "" when the chart counts rows instead of plotting a column.
+// Edit the entries during initialization to change the defaults.
+var ChartTypeSuggestions = map[[2]string]string{
+	{DataTypeDatetime, DataTypeNumeric}: "line",           // a trend over time
+	{DataTypeString, DataTypeNumeric}:   "bar",            // a value per category
+	{DataTypeBoolean, DataTypeNumeric}:  "bar",            // a value per flag
+	{DataTypeNumeric, DataTypeNumeric}:  ChartTypeScatter, // a correlation
+	{DataTypeDatetime, ""}:              "line",           // rows over time
+	{DataTypeString, ""}:                "pie",            // each category's share of the rows
+	{DataTypeBoolean, ""}:               "pie",            // each flag's share of the rows
+	{DataTypeNumeric, ""}:               "histogram",      // the distribution of a value
+}
+
+// DefaultSuggestedChartType is returned for type pairs without an entry in
+// ChartTypeSuggestions
+const DefaultSuggestedChartType = "bar"
+
+// SuggestChartType picks a default chart type for plotting y against x, e.g.
+// "line" for a timestamp X and a numeric Y. Pass a zero ColumnInfo as y for a
+// chart counting rows per X value. See ChartTypeSuggestions for the rules.
+func SuggestChartType(x ColumnInfo, y ColumnInfo) string {
+	key := [2]string{ClassifyDataType(x.DataType), ""}
+	if y.Name != "" || y.DataType != "" {
+		key[1] = ClassifyDataType(y.DataType)
+	}
+
+	if chartType, ok := ChartTypeSuggestions[key]; ok {
+		return chartType
+	}
+	return DefaultSuggestedChartType
+}
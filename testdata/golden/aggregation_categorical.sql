@@ -0,0 +1 @@
+SELECT o.region as x_value, string_agg(o.tag, $1) as y_value_1, mode() WITHIN GROUP (ORDER BY o.status) as y_value_2 FROM orders o GROUP BY o.region
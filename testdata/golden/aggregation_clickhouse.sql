@@ -0,0 +1 @@
+SELECT o.region as x_value, quantile(0.95)(o.amount) as y_value_1, median(o.amount) as y_value_2, stddevSamp(o.amount) as y_value_3 FROM "shop".orders o GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.region as x_value, COUNT(*) as y_value_1, COUNT(o.coupon) as y_value_2 FROM orders o GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.region as x_value, percentile_cont(0.95) WITHIN GROUP (ORDER BY o.latency) as p95 FROM orders o GROUP BY o.region
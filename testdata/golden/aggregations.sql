@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1, AVG(o.amount) as y_value_2, MIN(o.amount) as y_value_3, MAX(o.amount) as y_value_4, STDDEV(o.amount) as y_value_5, VARIANCE(o.amount) as y_value_6, percentile_cont(0.5) WITHIN GROUP (ORDER BY o.amount) as y_value_7, 100.0 * SUM(o.amount) / NULLIF(SUM(SUM(o.amount)) OVER (), 0) as y_value_8 FROM orders o GROUP BY o.region
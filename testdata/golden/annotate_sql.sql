@@ -0,0 +1 @@
+/* chart='Revenue  by region' chart_type='bar' */ SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.metadata#>>'{"utm","source"}' as x_value, SUM(o.amount) as y_value_1 FROM orders o GROUP BY o.metadata#>>'{"utm","source"}'
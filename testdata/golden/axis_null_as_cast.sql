@@ -0,0 +1 @@
+SELECT o.region as x_value, COALESCE(CAST(SUM(o.amount) AS double precision), $1) as y_value_1 FROM orders o GROUP BY o.region
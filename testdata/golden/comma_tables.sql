@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o, regions r WHERE o.amount > $1 AND r.code = $2 GROUP BY o.region
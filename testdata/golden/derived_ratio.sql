@@ -0,0 +1 @@
+SELECT o.region as x_value, CAST(SUM(o.signups) AS double precision) / NULLIF(SUM(o.visits), 0) as conversion, SUM(o.revenue) - COUNT(*) as y_value_2 FROM orders o GROUP BY o.region HAVING CAST(SUM(o.signups) AS double precision) / NULLIF(SUM(o.visits), 0) > $1
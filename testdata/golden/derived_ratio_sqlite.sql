@@ -0,0 +1 @@
+SELECT o.region as x_value, 1.0 * SUM(o.signups) / NULLIF(SUM(o.visits), 0) as conversion FROM orders o GROUP BY o.region
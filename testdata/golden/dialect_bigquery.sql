@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM `my-project.shop.orders` o WHERE o.status = @p1 AND o.amount BETWEEN @p2 AND @p3 GROUP BY o.region
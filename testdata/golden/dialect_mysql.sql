@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM `shop`.orders o WHERE o.status = ? AND o.amount BETWEEN ? AND ? GROUP BY o.region
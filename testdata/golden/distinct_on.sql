@@ -0,0 +1 @@
+SELECT DISTINCT ON (o.customer_id) o.customer_id as x_value, o.amount as y_value_1 FROM orders o ORDER BY o.customer_id ASC, o.created_at DESC
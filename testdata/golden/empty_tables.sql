@@ -0,0 +1 @@
+-- error: at least one table is required
@@ -0,0 +1 @@
+-- error: x_axis column is required
@@ -0,0 +1 @@
+-- error: at least one y-axis is required
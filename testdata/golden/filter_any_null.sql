@@ -0,0 +1 @@
+-- error: ANY operator cannot match NULL
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.tags <@ $1 GROUP BY o.region
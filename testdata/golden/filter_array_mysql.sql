@@ -0,0 +1 @@
+-- error: @> operator is only supported by the postgres dialect
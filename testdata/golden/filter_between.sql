@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.created_at BETWEEN $1 AND $2 GROUP BY o.region
@@ -0,0 +1 @@
+-- error: BETWEEN operator cannot have NULL values
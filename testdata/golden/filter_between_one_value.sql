@@ -0,0 +1 @@
+-- error: BETWEEN operator requires exactly 2 values at filter index 0, got 1
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM "shop".orders o WHERE o.paid = true AND (o.archived != true OR o.archived IS NULL) GROUP BY o.region
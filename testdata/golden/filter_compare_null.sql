@@ -0,0 +1 @@
+-- error: comparison operator > cannot compare with NULL
@@ -0,0 +1 @@
+-- error: IN operator requires 'values' array at filter index 0
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.shipped_at IS NULL AND o.paid IS TRUE AND o.archived IS FALSE GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.status = $1 AND o.amount > $2 AND o.region IN ($3, $4) GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE (o.status NOT IN ($1, $2) OR o.status IS NULL) GROUP BY o.region
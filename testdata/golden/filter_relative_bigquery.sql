@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.created_at >= TIMESTAMP(DATETIME_SUB(CURRENT_DATETIME(), INTERVAL @p1 DAY)) AND o.shipped_at BETWEEN CURRENT_TIMESTAMP() AND TIMESTAMP(DATETIME_ADD(CURRENT_DATETIME(), INTERVAL @p2 WEEK)) GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.created_at >= subtractDays(now(), ?) AND o.shipped_at BETWEEN now() AND addWeeks(now(), ?) GROUP BY o.region
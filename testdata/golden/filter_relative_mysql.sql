@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.created_at >= DATE_SUB(NOW(), INTERVAL ? DAY) AND o.shipped_at BETWEEN NOW() AND DATE_ADD(NOW(), INTERVAL ? WEEK) GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.created_at >= now() - make_interval(days => $1) AND o.shipped_at BETWEEN now() AND now() + make_interval(weeks => $2) GROUP BY o.region
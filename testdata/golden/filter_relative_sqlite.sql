@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.created_at >= datetime('now', ?) AND o.shipped_at BETWEEN datetime('now') AND datetime('now', ?) GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.paid = $1 AND o.flagged != $2 AND o.status IN ($3, $4) AND o.region NOT IN ($5) GROUP BY o.region
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE o.amount > ALL (SELECT amount FROM refunds) GROUP BY o.region
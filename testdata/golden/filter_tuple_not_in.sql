@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o WHERE (o.country, o.plan) NOT IN (($1, $2)) GROUP BY o.region
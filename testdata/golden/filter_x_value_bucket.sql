@@ -0,0 +1 @@
+WITH chart_data AS (SELECT DATE_TRUNC('month', o.created_at) as x_value, COALESCE(SUM(o.amount), $1) as y_value_1 FROM orders o WHERE DATE_TRUNC('month', o.created_at) BETWEEN $2 AND $3 GROUP BY DATE_TRUNC('month', o.created_at)), chart_series AS (SELECT generate_series(DATE_TRUNC('month', CAST($4 AS timestamptz)), CAST($5 AS timestamptz), CAST($6 AS interval)) as x_value) SELECT chart_series.x_value, COALESCE(chart_data.y_value_1, $7) as y_value_1 FROM chart_series LEFT JOIN chart_data ON chart_data.x_value = chart_series.x_value ORDER BY chart_series.x_value
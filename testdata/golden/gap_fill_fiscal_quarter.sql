@@ -0,0 +1 @@
+WITH chart_data AS (SELECT DATE_TRUNC('quarter', o.created_at - INTERVAL '3 months') + INTERVAL '3 months' as x_value, COALESCE(SUM(o.amount), $1) as y_value_1 FROM orders o GROUP BY DATE_TRUNC('quarter', o.created_at - INTERVAL '3 months') + INTERVAL '3 months'), chart_series AS (SELECT generate_series(DATE_TRUNC('quarter', CAST($2 AS timestamptz) - INTERVAL '3 months') + INTERVAL '3 months', CAST($3 AS timestamptz), CAST($4 AS interval)) as x_value) SELECT chart_series.x_value, COALESCE(chart_data.y_value_1, $5) as y_value_1 FROM chart_series LEFT JOIN chart_data ON chart_data.x_value = chart_series.x_value ORDER BY chart_series.x_value
@@ -0,0 +1 @@
+SELECT o.region as x_value, o.amount as y_value_1 FROM orders o
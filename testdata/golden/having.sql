@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as total FROM orders o GROUP BY o.region HAVING SUM(o.amount) > $1 AND COUNT(*) >= $2
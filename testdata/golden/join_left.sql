@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o LEFT JOIN customers c ON c.id = o.customer_id GROUP BY o.region
@@ -0,0 +1 @@
+-- error: joins at table index 1 must be declared on the first table
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o GROUP BY o.region ORDER BY y_value_1 DESC FETCH FIRST 10 ROWS WITH TIES
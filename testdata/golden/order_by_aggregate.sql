@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1 FROM orders o GROUP BY o.region ORDER BY COUNT(*) DESC, MAX(o.amount) ASC LIMIT 10
@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as revenue, SUM(o.amount) FILTER (WHERE o.status = $1) as refunded, COUNT(*) FILTER (WHERE o.amount >= $2 AND o.status IN ($3, $4)) as large_orders FROM orders o WHERE o.created_at BETWEEN $5 AND $6 GROUP BY o.region
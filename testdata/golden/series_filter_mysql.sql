@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as revenue, SUM(CASE WHEN o.status = ? THEN o.amount END) as refunded, COUNT(CASE WHEN o.amount >= ? AND o.status IN (?, ?) THEN 1 END) as large_orders FROM orders o WHERE o.created_at BETWEEN ? AND ? GROUP BY o.region
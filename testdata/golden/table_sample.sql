@@ -0,0 +1 @@
+SELECT o.quantity as x_value, o.amount as y_value_1 FROM orders o TABLESAMPLE BERNOULLI (0.5) WHERE o.amount > $1
@@ -0,0 +1 @@
+SELECT r.name as x_value, SUM(o.amount) as y_value_1 FROM users u INNER JOIN orders o ON o.user_id = u.id LEFT JOIN users referrer ON referrer.id = u.referred_by LEFT JOIN regions r ON r.code = referrer.region GROUP BY r.name
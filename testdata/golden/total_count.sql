@@ -0,0 +1 @@
+SELECT o.region as x_value, SUM(o.amount) as y_value_1, COUNT(*) OVER () as total_count FROM orders o WHERE o.amount > $1 GROUP BY o.region ORDER BY y_value_1 DESC LIMIT 20
@@ -0,0 +1,257 @@
+package chatabase
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// How MovingAverage treats NULL cells
+const (
+	// NullsSkip leaves NULL cells NULL and out of their neighbors' averages
+	NullsSkip = "skip"
+	// NullsCarryForward replaces a NULL with the last value before it
+	NullsCarryForward = "carry_forward"
+)
+
+// MovingAverageOptions controls MovingAverageWithOptions
+type MovingAverageOptions struct {
+	// Nulls is NullsSkip (default) or NullsCarryForward
+	Nulls string
+}
+
+// MovingAverage returns a copy of rows in which the Y series at seriesIndex
+// is smoothed: each value is the mean of it and the window-1 values before
+// it, fewer at the start. NULL cells are skipped; see MovingAverageOptions.
+// The X values, row order and other series are unchanged, and rows is not
+// modified. It returns nil when window < 1 or seriesIndex is out of range;
+// MovingAverageWithOptions reports why.
+func MovingAverage(rows []ChartDataRow, window int, seriesIndex int) []ChartDataRow {
+	smoothed, err := MovingAverageWithOptions(rows, window, seriesIndex, MovingAverageOptions{})
+	if err != nil {
+		return nil
+	}
+	return smoothed
+}
+
+// MovingAverageWithOptions is MovingAverage with options choosing the NULL
+// handling, returning an error for an invalid window, series or option
+func MovingAverageWithOptions(rows []ChartDataRow, window int, seriesIndex int, opts MovingAverageOptions) ([]ChartDataRow, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("moving average window must be at least 1, got %d", window)
+	}
+	if opts.Nulls != "" && opts.Nulls != NullsSkip && opts.Nulls != NullsCarryForward {
+		return nil, fmt.Errorf("invalid nulls '%s'. Must be one of: %s, %s", opts.Nulls, NullsSkip, NullsCarryForward)
+	}
+	values, err := seriesValues(rows, seriesIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Nulls == NullsCarryForward {
+		var last *float64
+		for i, value := range values {
+			if value == nil {
+				values[i] = last
+			}
+			last = values[i]
+		}
+	}
+
+	result := copyRows(rows)
+	for i, value := range values {
+		var smoothed interface{}
+		if value != nil {
+			sum, n := 0.0, 0
+			for j := i; j >= 0 && j > i-window; j-- {
+				if values[j] != nil {
+					sum += *values[j]
+					n++
+				}
+			}
+			smoothed = sum / float64(n)
+		}
+		setSeriesValue(&result[i], seriesIndex, smoothed)
+	}
+	return result, nil
+}
+
+// CumulativeSum returns a copy of rows in which the Y series at seriesIndex
+// is a running total: each value is the sum of it and every value before
+// it, as for a burn-up chart. NULL and non-numeric cells count as 0, so they
+// repeat the total so far. The X values, row order and other series are
+// unchanged, and rows is not modified. It returns nil when seriesIndex is
+// out of range.
+func CumulativeSum(rows []ChartDataRow, seriesIndex int) []ChartDataRow {
+	values, err := seriesValues(rows, seriesIndex)
+	if err != nil {
+		return nil
+	}
+
+	result := copyRows(rows)
+	total := 0.0
+	for i, value := range values {
+		if value != nil {
+			total += *value
+		}
+		setSeriesValue(&result[i], seriesIndex, total)
+	}
+	return result
+}
+
+// TopNWithOther returns the n rows with the largest values of the Y series
+// at seriesIndex, largest first, followed by one row labeled otherLabel
+// holding the sum of the remaining rows for every series, e.g. the top 5
+// slices of a pie and an "Other" slice. Ties keep their original order and
+// NULL values sort last. Sums keep the cells' type where they share one:
+// integers sum to int64, decimals to decimal.Decimal, numeric strings to a
+// string; mixed cells sum to float64. When there are at most n rows, they
+// are returned sorted without an Other row. rows is not modified. It returns
+// nil when n < 1 or seriesIndex is out of range.
+func TopNWithOther(rows []ChartDataRow, n int, seriesIndex int, otherLabel string) []ChartDataRow {
+	if n < 1 {
+		return nil
+	}
+	if _, err := seriesValues(rows, seriesIndex); err != nil {
+		return nil
+	}
+
+	sorted := copyRows(rows)
+	keys := make([]*float64, len(sorted))
+	order := make([]int, len(sorted))
+	for i := range sorted {
+		keys[i] = sortValue(&sorted[i], seriesIndex)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := keys[order[a]], keys[order[b]]
+		if x == nil || y == nil {
+			return x != nil
+		}
+		return *x > *y
+	})
+
+	result := make([]ChartDataRow, 0, n+1)
+	for _, i := range order {
+		result = append(result, sorted[i])
+	}
+	if len(result) <= n {
+		return result
+	}
+
+	rest := result[n:]
+	other := ChartDataRow{XValue: otherLabel, Series: append([]string(nil), rest[0].Series...)}
+	values := make(map[string]interface{}, len(other.Series))
+	for _, name := range other.Series {
+		cells := make([]interface{}, len(rest))
+		for i, row := range rest {
+			cells[i] = row.YValues.(map[string]interface{})[name]
+		}
+		values[name] = sumCells(cells)
+	}
+	other.YValues = values
+	return append(result[:n:n], other)
+}
+
+// sortValue returns the Y series at index of row as a float for ordering,
+// or nil for NULL and non-numeric cells
+func sortValue(row *ChartDataRow, index int) *float64 {
+	if f := row.GetYValueAsFloat(index); f != nil {
+		return f
+	}
+	if d := row.GetYValueAsDecimal(index); d != nil {
+		f := d.InexactFloat64()
+		return &f
+	}
+	return nil
+}
+
+// sumCells sums scanned cells, keeping their type when they share one. NULL
+// and non-numeric cells are skipped; the sum of none is NULL.
+func sumCells(cells []interface{}) interface{} {
+	var ints int64
+	var decimals decimal.Decimal
+	var floats float64
+	kind := ""
+	for _, cell := range cells {
+		var cellKind string
+		switch v := cell.(type) {
+		case int64:
+			ints, cellKind = ints+v, "int"
+		case int32:
+			ints, cellKind = ints+int64(v), "int"
+		case int:
+			ints, cellKind = ints+int64(v), "int"
+		case decimal.Decimal:
+			decimals, cellKind = decimals.Add(v), "decimal"
+		case string:
+			d, err := decimal.NewFromString(v)
+			if err != nil {
+				continue
+			}
+			decimals, cellKind = decimals.Add(d), "string"
+		case float64:
+			floats, cellKind = floats+v, "float"
+		case float32:
+			floats, cellKind = floats+float64(v), "float"
+		default:
+			continue
+		}
+		if kind == "" {
+			kind = cellKind
+		} else if kind != cellKind {
+			kind = "mixed"
+		}
+	}
+
+	switch kind {
+	case "":
+		return nil
+	case "int":
+		return ints
+	case "decimal":
+		return decimals
+	case "string":
+		return decimals.String()
+	case "float":
+		return floats
+	default:
+		return floats + float64(ints) + decimals.InexactFloat64()
+	}
+}
+
+// seriesValues returns the Y series at index of every row as floats, nil for
+// NULL and non-numeric cells. It fails when index is not a series of rows.
+func seriesValues(rows []ChartDataRow, index int) ([]*float64, error) {
+	values := make([]*float64, len(rows))
+	for i := range rows {
+		if index < 0 || index >= len(rows[i].Series) {
+			return nil, fmt.Errorf("series index %d is out of range: row %d has %d series", index, i, len(rows[i].Series))
+		}
+		values[i] = rows[i].GetYValueAsFloat(index)
+	}
+	return values, nil
+}
+
+// copyRows returns a copy of rows whose YValues maps and Series slices can
+// be changed without affecting rows
+func copyRows(rows []ChartDataRow) []ChartDataRow {
+	copied := make([]ChartDataRow, len(rows))
+	for i, row := range rows {
+		values, _ := row.YValues.(map[string]interface{})
+		yValues := make(map[string]interface{}, len(values))
+		for key, value := range values {
+			yValues[key] = value
+		}
+		row.YValues = yValues
+		row.Series = append([]string(nil), row.Series...)
+		copied[i] = row
+	}
+	return copied
+}
+
+// setSeriesValue sets the Y series at index of a row copied by copyRows
+func setSeriesValue(row *ChartDataRow, index int, value interface{}) {
+	row.YValues.(map[string]interface{})[row.Series[index]] = value
+}
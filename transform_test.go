@@ -0,0 +1,105 @@
+package chatabase
+
+import (
+	"reflect"
+	"testing"
+)
+
+// seriesRows returns one row per value, numbered from 1, with the value as
+// series "y" and a constant series "other"
+func seriesRows(values ...interface{}) []ChartDataRow {
+	rows := make([]ChartDataRow, len(values))
+	for i, value := range values {
+		rows[i] = ChartDataRow{
+			XValue:  i + 1,
+			YValues: map[string]interface{}{"y": value, "other": "unchanged"},
+			Series:  []string{"y", "other"},
+		}
+	}
+	return rows
+}
+
+// seriesOf returns the "y" values of rows
+func seriesOf(rows []ChartDataRow) []interface{} {
+	values := make([]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = row.YValues.(map[string]interface{})["y"]
+	}
+	return values
+}
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		window int
+		nulls  string
+		want   []interface{}
+	}{
+		{
+			name:   "window 3",
+			values: []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5), int64(6)},
+			window: 3,
+			want:   []interface{}{1.0, 1.5, 2.0, 3.0, 4.0, 5.0},
+		},
+		{
+			name:   "window 1 keeps the values",
+			values: []interface{}{2.0, 4.0, 8.0},
+			window: 1,
+			want:   []interface{}{2.0, 4.0, 8.0},
+		},
+		{
+			name:   "window longer than the series",
+			values: []interface{}{3.0, 6.0, 9.0},
+			window: 10,
+			want:   []interface{}{3.0, 4.5, 6.0},
+		},
+		{
+			name:   "NULLs skipped",
+			values: []interface{}{2.0, nil, 4.0, 8.0},
+			window: 2,
+			want:   []interface{}{2.0, nil, 4.0, 6.0},
+		},
+		{
+			name:   "NULLs carried forward",
+			values: []interface{}{2.0, nil, 4.0, 8.0},
+			window: 2,
+			nulls:  NullsCarryForward,
+			want:   []interface{}{2.0, 2.0, 3.0, 6.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := seriesRows(tt.values...)
+			smoothed, err := MovingAverageWithOptions(rows, tt.window, 0, MovingAverageOptions{Nulls: tt.nulls})
+			if err != nil {
+				t.Fatalf("MovingAverageWithOptions() error = %v", err)
+			}
+			if got := seriesOf(smoothed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("smoothed = %v, want %v", got, tt.want)
+			}
+			if got := seriesOf(rows); !reflect.DeepEqual(got, tt.values) {
+				t.Errorf("input rows were modified: %v, want %v", got, tt.values)
+			}
+			for i, row := range smoothed {
+				if row.XValue != i+1 || row.YValues.(map[string]interface{})["other"] != "unchanged" {
+					t.Errorf("row %d x or other series changed: %+v", i, row)
+				}
+			}
+		})
+	}
+}
+
+func TestMovingAverageInvalid(t *testing.T) {
+	rows := seriesRows(1.0, 2.0)
+	if got := MovingAverage(rows, 0, 0); got != nil {
+		t.Errorf("MovingAverage() with window 0 = %v, want nil", got)
+	}
+	if got := MovingAverage(rows, 2, 2); got != nil {
+		t.Errorf("MovingAverage() with series out of range = %v, want nil", got)
+	}
+	if _, err := MovingAverageWithOptions(rows, 2, 0, MovingAverageOptions{Nulls: "zero"}); err == nil {
+		t.Error("MovingAverageWithOptions() accepted an unknown nulls option")
+	}
+}
@@ -0,0 +1,64 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildUnionQuery builds each config's chart query and combines them with
+// UNION ALL, or UNION when unionAll is false, e.g. this year and last year
+// as two differently filtered queries of one chart. The placeholders are
+// numbered across the whole query and the args follow in config order.
+//
+// Every config must use the same dialect and select the same number of
+// columns: x_value, its Y series and total_count when IncludeTotalCount is
+// set. Each query is wrapped in a subquery so its own ORDER BY and LIMIT
+// still apply. Like BuildChartQuery, the configs are not validated.
+func BuildUnionQuery(configs []*ChartConfig, unionAll bool) (string, []interface{}, error) {
+	if len(configs) == 0 {
+		return "", nil, fmt.Errorf("union needs at least one chart config")
+	}
+	for i, config := range configs {
+		if config == nil {
+			return "", nil, fmt.Errorf("chart config at index %d is nil", i)
+		}
+	}
+
+	first := configs[0]
+	columns := selectColumnCount(first)
+	for i, config := range configs[1:] {
+		if dialectOf(config) != dialectOf(first) {
+			return "", nil, fmt.Errorf("chart config at index %d uses dialect '%s' but index 0 uses '%s'", i+1, dialectOf(config), dialectOf(first))
+		}
+		if n := selectColumnCount(config); n != columns {
+			return "", nil, fmt.Errorf("chart config at index %d selects %d columns but index 0 selects %d", i+1, n, columns)
+		}
+	}
+
+	operator := " UNION "
+	if unionAll {
+		operator = " UNION ALL "
+	}
+
+	// One binder numbers the placeholders of every query in order
+	b := newArgBinder(first)
+	parts := make([]string, len(configs))
+	for i, config := range configs {
+		query, next, err := buildChartQuery(config, b)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build chart config at index %d: %w", i, err)
+		}
+		b = next
+		parts[i] = fmt.Sprintf("SELECT * FROM (%s) AS union_%d", query, i+1)
+	}
+	return strings.Join(parts, operator), b.args, nil
+}
+
+// selectColumnCount returns the number of columns config's query selects
+func selectColumnCount(config *ChartConfig) int {
+	n := 1 + len(config.YAxis)
+	if config.IncludeTotalCount {
+		n++
+	}
+	return n
+}
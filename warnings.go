@@ -0,0 +1,103 @@
+package chatabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WarningCheck inspects a valid configuration and returns warnings for
+// choices that are legal but likely mistakes
+type WarningCheck func(config *ChartConfig) []string
+
+// LargeLimitWarning is the LIMIT above which ValidateWithWarnings warns
+const LargeLimitWarning = 100000
+
+// WarningChecks are run by ValidateWithWarnings, in order. Append to it at
+// init time to add project-specific checks. The built-in checks warn about:
+//
+//   - a LIMIT above LargeLimitWarning
+//   - a LIKE/ILIKE filter whose pattern has no % or _ wildcard
+//   - a GROUP BY with no aggregated axis
+//   - an aggregated Y axis next to an X axis that is not grouped
+//   - repeated or contradictory filters, see OptimizeFilters
+var WarningChecks = []WarningCheck{
+	warnLargeLimit,
+	warnLikeWithoutWildcard,
+	warnGroupByWithoutAggregation,
+	warnUngroupedXAxis,
+	warnRedundantFilters,
+}
+
+// ValidateWithWarnings validates config like Validate and, when it is valid,
+// also returns the non-fatal warnings of WarningChecks for a UI to surface
+func ValidateWithWarnings(config *ChartConfig) (warnings []string, err error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, check := range WarningChecks {
+		warnings = append(warnings, check(config)...)
+	}
+	return warnings, nil
+}
+
+func warnLargeLimit(config *ChartConfig) []string {
+	if config.Limit > LargeLimitWarning {
+		return []string{fmt.Sprintf("limit %d is very large; charts rarely need more than %d rows", config.Limit, LargeLimitWarning)}
+	}
+	return nil
+}
+
+func warnLikeWithoutWildcard(config *ChartConfig) []string {
+	var warnings []string
+	for i, filter := range config.Filters {
+		switch strings.ToUpper(filter.Operator) {
+		case "LIKE", "ILIKE", "NOT LIKE", "NOT ILIKE":
+		default:
+			continue
+		}
+		pattern, ok := filter.Value.(string)
+		if ok && !strings.ContainsAny(pattern, "%_") {
+			warnings = append(warnings, fmt.Sprintf("%s filter at index %d has no %% or _ wildcard and matches '%s' exactly; did you mean '='?", filter.Operator, i, pattern))
+		}
+	}
+	return warnings
+}
+
+func warnGroupByWithoutAggregation(config *ChartConfig) []string {
+	if len(config.GroupBy) == 0 && len(config.GroupingSets) == 0 {
+		return nil
+	}
+	if config.XAxis.Aggregation != "" {
+		return nil
+	}
+	for _, yAxis := range config.YAxis {
+		if yAxis.aggregated() {
+			return nil
+		}
+	}
+	return []string{"group_by is set but no axis is aggregated; did you forget an aggregation?"}
+}
+
+func warnUngroupedXAxis(config *ChartConfig) []string {
+	if config.XAxis.Aggregation != "" || len(config.GroupBy) == 0 || contains(config.GroupBy, config.XAxis.Column) {
+		return nil
+	}
+	for _, yAxis := range config.YAxis {
+		if yAxis.aggregated() {
+			return []string{fmt.Sprintf("y_axis is aggregated but x_axis column '%s' is not in group_by", config.XAxis.Column)}
+		}
+	}
+	return nil
+}
+
+func warnRedundantFilters(config *ChartConfig) []string {
+	var warnings []string
+	if repeated := len(config.Filters) - len(dedupeFilters(config.Filters)); repeated > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d filter(s) repeat an earlier filter exactly; OptimizeFilters removes them", repeated))
+	}
+	if err := findContradiction(config.Filters); err != nil {
+		warnings = append(warnings, err.Error()+"; the chart will be empty")
+	}
+	return warnings
+}